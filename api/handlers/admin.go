@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// isAdminRequest reports whether the request carries adminToken as a bearer token.
+// Admin features are disabled when no token is configured.
+func isAdminRequest(r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/igorsal/pr-documentator/api/middleware"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+type DebugHandler struct {
+	schema     interfaces.ToolSchemaReporter
+	adminToken string
+	logger     interfaces.Logger
+}
+
+// NewDebugHandler creates a handler exposing admin-only debugging information
+func NewDebugHandler(schema interfaces.ToolSchemaReporter, adminToken string, logger interfaces.Logger) *DebugHandler {
+	return &DebugHandler{
+		schema:     schema,
+		adminToken: adminToken,
+		logger:     logger,
+	}
+}
+
+// ToolSchema serves GET /debug/tool-schema; ?system_prompt=true includes the system prompt
+func (h *DebugHandler) ToolSchema(w http.ResponseWriter, r *http.Request) {
+	if !isAdminRequest(r, h.adminToken) {
+		if err := middleware.WriteErrorResponse(w, http.StatusUnauthorized, pkgerrors.NewUnauthorizedError("tool schema requires admin authorization")); err != nil {
+			h.logger.Error("Failed to encode debug error response", err)
+		}
+		return
+	}
+
+	includeSystemPrompt, _ := strconv.ParseBool(r.URL.Query().Get("system_prompt"))
+	schema, err := h.schema.ToolSchema(includeSystemPrompt)
+	if err != nil {
+		h.logger.Error("Failed to build tool schema", err)
+		if err := middleware.WriteErrorResponse(w, http.StatusInternalServerError, pkgerrors.NewInternalError("failed to build tool schema").WithCause(err)); err != nil {
+			h.logger.Error("Failed to encode debug error response", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(schema); err != nil {
+		h.logger.Error("Failed to write tool schema response", err)
+	}
+}
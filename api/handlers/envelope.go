@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// AnalysisEnvelope wraps analysis results returned by every analyze endpoint
+type AnalysisEnvelope struct {
+	Status           string                   `json:"status"` // success or partial
+	Analysis         *models.AnalysisResponse `json:"analysis"`
+	Timestamp        string                   `json:"timestamp"`
+	PostmanFailed    bool                     `json:"postman_failed,omitempty"`
+	PostmanErrorType string                   `json:"postman_error_type,omitempty"`
+	PostmanErrorCode string                   `json:"postman_error_code,omitempty"`
+}
+
+// newAnalysisEnvelope reports a partial result when the analysis succeeded but docs weren't
+// updated, so CI can alert on it
+func newAnalysisEnvelope(analysis *models.AnalysisResponse) AnalysisEnvelope {
+	envelope := AnalysisEnvelope{
+		Status:    "success",
+		Analysis:  analysis,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if analysis.PostmanUpdate.Failed() {
+		envelope.Status = "partial"
+		envelope.PostmanFailed = true
+		envelope.PostmanErrorType = analysis.PostmanUpdate.ErrorType
+		envelope.PostmanErrorCode = analysis.PostmanUpdate.ErrorCode
+	}
+	return envelope
+}
+
+// writeAnalysis writes the analysis in the configured envelope format. Partial results are
+// answered with 207 Multi-Status in either format. Unsettled results are marked no-store so they
+// are not replayed to redeliveries.
+func writeAnalysis(w http.ResponseWriter, format string, analysis *models.AnalysisResponse) (AnalysisEnvelope, error) {
+	envelope := newAnalysisEnvelope(analysis)
+
+	statusCode := http.StatusOK
+	if envelope.PostmanFailed {
+		statusCode = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !analysis.PostmanUpdate.Settled() {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	w.WriteHeader(statusCode)
+
+	if format == config.ResponseEnvelopeRaw {
+		return envelope, json.NewEncoder(w).Encode(analysis)
+	}
+	return envelope, json.NewEncoder(w).Encode(envelope)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/igorsal/pr-documentator/api/middleware"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/asyncapi"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// DefaultAsyncAPITitle titles exported documents when neither ?title nor the analysis names one
+const DefaultAsyncAPITitle = "PR Documentator"
+
+type ExportHandler struct {
+	logger interfaces.Logger
+}
+
+// NewExportHandler creates a handler converting analysis results to other documentation formats
+func NewExportHandler(logger interfaces.Logger) *ExportHandler {
+	return &ExportHandler{logger: logger}
+}
+
+// AsyncAPI serves POST /export/asyncapi: the body is an analysis result, wrapped or raw as returned
+// by the analyze endpoints, and the response its channels as an AsyncAPI 2.6 document.
+// ?title= names the document, defaulting to the analyzed repository.
+func (h *ExportHandler) AsyncAPI(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, pkgerrors.NewValidationError("failed to read request body").WithCode(pkgerrors.CodeInvalidRequestBody))
+		return
+	}
+
+	// Accept the wrapped envelope as well as a bare analysis
+	var envelope struct {
+		Analysis json.RawMessage `json:"analysis"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Analysis) > 0 {
+		body = envelope.Analysis
+	}
+
+	analysis, err := models.DecodeAnalysis(body)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, pkgerrors.NewValidationError("invalid analysis: "+err.Error()).WithCode(pkgerrors.CodeInvalidRequestBody))
+		return
+	}
+
+	title := r.URL.Query().Get("title")
+	if title == "" {
+		title = analysis.Repository
+	}
+	if title == "" {
+		title = DefaultAsyncAPITitle
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(asyncapi.FromAnalysis(analysis, title)); err != nil {
+		h.logger.Error("Failed to encode AsyncAPI document", err)
+	}
+}
+
+func (h *ExportHandler) writeError(w http.ResponseWriter, status int, appErr *pkgerrors.AppError) {
+	if err := middleware.WriteErrorResponse(w, status, appErr); err != nil {
+		h.logger.Error("Failed to encode export error response", err)
+	}
+}
@@ -3,28 +3,55 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"runtime"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 )
 
 type HealthHandler struct {
-	logger  interfaces.Logger
-	metrics interfaces.MetricsCollector
+	adminToken string
+	analyzer   interfaces.InFlightReporter
+	breakers   []interfaces.CircuitBreaker
+	startedAt  time.Time
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
 }
 
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Version   string `json:"version"`
+	Status    string         `json:"status"`
+	Timestamp string         `json:"timestamp"`
+	Version   string         `json:"version"`
+	Details   *HealthDetails `json:"details,omitempty"`
 }
 
-// NewHealthHandler creates a new health handler
-func NewHealthHandler(logger interfaces.Logger, metrics interfaces.MetricsCollector) *HealthHandler {
+// HealthDetails are returned for GET /health?verbose=true
+type HealthDetails struct {
+	CircuitBreakers  map[string]string `json:"circuit_breakers"`
+	InFlightAnalyses int64             `json:"in_flight_analyses"`
+	Runtime          *RuntimeDetails   `json:"runtime,omitempty"` // admin only
+}
+
+// RuntimeDetails describe the process and are only shown to admins
+type RuntimeDetails struct {
+	GoVersion      string  `json:"go_version"`
+	Goroutines     int     `json:"goroutines"`
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes"`
+	UptimeSeconds  float64 `json:"uptime_seconds"`
+}
+
+// NewHealthHandler creates a new health handler. analyzer and breakers feed the verbose report;
+// runtime details additionally require adminToken as a bearer token and are disabled when it is empty.
+func NewHealthHandler(adminToken string, analyzer interfaces.InFlightReporter, breakers []interfaces.CircuitBreaker, logger interfaces.Logger, metrics interfaces.MetricsCollector) *HealthHandler {
 	return &HealthHandler{
-		logger:  logger,
-		metrics: metrics,
+		adminToken: adminToken,
+		analyzer:   analyzer,
+		breakers:   breakers,
+		startedAt:  time.Now(),
+		logger:     logger,
+		metrics:    metrics,
 	}
 }
 
@@ -44,6 +71,15 @@ func (h *HealthHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		Version:   version,
 	}
 
+	if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); verbose {
+		response.Details = h.details(r)
+		for _, state := range response.Details.CircuitBreakers {
+			if state == "open" {
+				response.Status = "degraded"
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -56,6 +92,32 @@ func (h *HealthHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	h.logger.Debug("Health check completed successfully")
 }
 
+// details collects operational state, including runtime details for authenticated admins
+func (h *HealthHandler) details(r *http.Request) *HealthDetails {
+	details := &HealthDetails{
+		CircuitBreakers: make(map[string]string, len(h.breakers)),
+	}
+	for _, cb := range h.breakers {
+		details.CircuitBreakers[cb.Name()] = cb.State()
+	}
+	if h.analyzer != nil {
+		details.InFlightAnalyses = h.analyzer.InFlight()
+	}
+
+	if isAdminRequest(r, h.adminToken) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		details.Runtime = &RuntimeDetails{
+			GoVersion:      runtime.Version(),
+			Goroutines:     runtime.NumGoroutine(),
+			HeapAllocBytes: mem.HeapAlloc,
+			UptimeSeconds:  time.Since(h.startedAt).Seconds(),
+		}
+	}
+
+	return details
+}
+
 // getVersion returns build version information
 func getVersion() string {
 	if info, ok := debug.ReadBuildInfo(); ok {
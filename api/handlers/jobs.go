@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/igorsal/pr-documentator/api/middleware"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+type JobsHandler struct {
+	jobs   interfaces.JobReporter
+	logger interfaces.Logger
+}
+
+// NewJobsHandler creates a handler reporting background Postman updates
+func NewJobsHandler(jobs interfaces.JobReporter, logger interfaces.Logger) *JobsHandler {
+	return &JobsHandler{
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+// Handle serves GET /jobs/{id}
+func (h *JobsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	job, ok := h.jobs.PostmanJob(id)
+	if !ok {
+		if err := middleware.WriteErrorResponse(w, http.StatusNotFound, pkgerrors.NewNotFoundError("job not found")); err != nil {
+			h.logger.Error("Failed to encode job error response", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.Error("Failed to encode job response", err, "job_id", id)
+	}
+}
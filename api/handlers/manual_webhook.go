@@ -1,55 +1,108 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/igorsal/pr-documentator/api/middleware"
+	"github.com/igorsal/pr-documentator/internal/config"
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/models"
 	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/validator"
 )
 
 const (
 	MaxBodySize = 10 * 1024 * 1024 // 10MB max
+
+	// AnalysisTimeoutHeader lets a caller override the analysis timeout for a single request
+	AnalysisTimeoutHeader = "X-Analysis-Timeout"
+	// DebugPromptHeader asks for the Claude requests to be included in the response (admin only)
+	DebugPromptHeader = "X-Debug-Prompt"
 )
 
 type ManualWebhookHandler struct {
-	analyzer interfaces.AnalyzerService
-	logger   interfaces.Logger
-	metrics  interfaces.MetricsCollector
+	analyzer    interfaces.AnalyzerService
+	maxBodySize int64
+	config      config.AnalyzerConfig
+	adminToken  string
+	envelope    string
+	logger      interfaces.Logger
+	metrics     interfaces.MetricsCollector
 }
 
 type ManualWebhookRequest struct {
-	Diff string `json:"diff" validate:"required"`
+	Diff         string `json:"diff" validate:"required"`
+	CollectionID string `json:"collection_id,omitempty"`
 }
 
-func NewManualWebhookHandler(analyzer interfaces.AnalyzerService, logger interfaces.Logger, metrics interfaces.MetricsCollector) *ManualWebhookHandler {
+// NewManualWebhookHandler creates a new manual analysis handler. cfg.MaxDiffBytes bounds the request body,
+// falling back to MaxBodySize when not positive. adminToken enables the X-Debug-Prompt header and the
+// collection_id override.
+// Results are written in the given envelope format.
+func NewManualWebhookHandler(analyzer interfaces.AnalyzerService, cfg config.AnalyzerConfig, adminToken, envelope string, logger interfaces.Logger, metrics interfaces.MetricsCollector) *ManualWebhookHandler {
+	maxBodySize := int64(MaxBodySize)
+	if cfg.MaxDiffBytes > 0 {
+		// Leave room for the JSON envelope around the diff
+		maxBodySize = int64(cfg.MaxDiffBytes) + 1024
+	}
+
 	return &ManualWebhookHandler{
-		analyzer: analyzer,
-		logger:   logger,
-		metrics:  metrics,
+		analyzer:    analyzer,
+		maxBodySize: maxBodySize,
+		config:      cfg,
+		adminToken:  adminToken,
+		envelope:    envelope,
+		logger:      logger,
+		metrics:     metrics,
 	}
 }
 
 func (h *ManualWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		h.writeErrorResponse(w, pkgerrors.NewValidationError("method not allowed"), http.StatusMethodNotAllowed)
+		h.writeErrorResponse(w, pkgerrors.NewValidationError("method not allowed").WithCode(pkgerrors.CodeMethodNotAllowed), http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Parse request body
-	var req ManualWebhookRequest
-	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize)).Decode(&req); err != nil {
-		h.logger.Error("Failed to decode manual webhook request", err)
-		h.writeErrorResponse(w, pkgerrors.NewValidationError("invalid request body"), http.StatusBadRequest)
+	timeout, err := h.analysisTimeout(r)
+	if err != nil {
+		h.writeErrorResponse(w, err, http.StatusBadRequest)
 		return
 	}
 
-	if req.Diff == "" {
-		h.writeErrorResponse(w, pkgerrors.NewValidationError("diff field is required"), http.StatusBadRequest)
+	// Read the diff from a JSON body, a raw patch or a multipart upload
+	diff, collectionID, err := h.readDiff(w, r)
+	if err != nil {
+		h.logger.Error("Failed to read manual analysis request", err, "content_type", r.Header.Get("Content-Type"))
+		h.writeErrorResponse(w, pkgerrors.NewValidationError("invalid request body").WithCode(pkgerrors.CodeInvalidRequestBody), http.StatusBadRequest)
 		return
 	}
 
+	if err := validator.Struct(ManualWebhookRequest{Diff: strings.TrimSpace(diff), CollectionID: collectionID}); err != nil {
+		h.writeErrorResponse(w, validator.ToAppError(err, "diff field is required").WithCode(pkgerrors.CodeDiffRequired), http.StatusBadRequest)
+		return
+	}
+
+	// The override is written with the service's Postman key, so only admins may redirect it
+	if collectionID != "" {
+		if !isAdminRequest(r, h.adminToken) {
+			h.writeErrorResponse(w, pkgerrors.NewUnauthorizedError("collection_id requires admin authorization"), http.StatusUnauthorized)
+			return
+		}
+		if !models.ValidCollectionID(collectionID) {
+			h.writeErrorResponse(w, pkgerrors.NewValidationError(fmt.Sprintf("invalid collection_id %q", collectionID)).
+				WithCode(pkgerrors.CodeInvalidCollectionID), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Create a mock payload for manual analysis
 	payload := models.GitHubPRPayload{
 		Action: "opened",
@@ -62,41 +115,55 @@ func (h *ManualWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			Body:    "Manual analysis triggered via webhook",
 			DiffURL: "manual",
 		},
-		Diff: req.Diff,
+		Diff:         diff,
+		CollectionID: collectionID,
+		Source:       models.PayloadSourceManual,
 	}
 
-	// Analyze the diff
-	result, err := h.analyzer.AnalyzePR(r.Context(), payload)
-	if err != nil {
-		h.logger.Error("Failed to analyze manual diff", err)
+	// Analyze the diff, extending the write deadline so long analyses can still respond
+	ctx := r.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 5*time.Second)); err != nil {
+			h.logger.Warn("Could not extend write deadline for manual analysis", "error", err)
+		}
+	}
+
+	// Re-run the analysis even when an identical request was analyzed recently
+	if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); force {
+		ctx = models.WithForce(ctx)
+	}
+
+	// Preview the Postman changes as a unified diff without writing them
+	if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run")); dryRun {
+		ctx = models.WithDryRun(ctx)
+	}
 
-		var statusCode int
-		if appErr, ok := pkgerrors.AsAppError(err); ok {
-			switch appErr.Type {
-			case pkgerrors.ErrorTypeValidation:
-				statusCode = http.StatusBadRequest
-			case pkgerrors.ErrorTypeUnauthorized:
-				statusCode = http.StatusUnauthorized
-			case pkgerrors.ErrorTypeRateLimit:
-				statusCode = http.StatusTooManyRequests
-			case pkgerrors.ErrorTypeUnavailable:
-				statusCode = http.StatusServiceUnavailable
-			default:
-				statusCode = http.StatusInternalServerError
-			}
-		} else {
-			statusCode = http.StatusInternalServerError
+	// Admins can ask for the exact Claude requests to diagnose surprising results
+	var promptDebug *models.PromptDebug
+	if debug, _ := strconv.ParseBool(r.Header.Get(DebugPromptHeader)); debug {
+		if !isAdminRequest(r, h.adminToken) {
+			h.writeErrorResponse(w, pkgerrors.NewUnauthorizedError(DebugPromptHeader+" requires admin authorization"), http.StatusUnauthorized)
+			return
 		}
+		ctx, promptDebug = models.WithPromptDebug(ctx)
+	}
 
-		h.writeErrorResponse(w, err, statusCode)
+	result, err := h.analyzer.AnalyzePR(ctx, payload)
+	if err != nil {
+		h.logger.Error("Failed to analyze manual diff", err)
+		h.writeErrorResponse(w, err, pkgerrors.HTTPStatus(err))
 		return
 	}
 
-	// Return analysis result
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	if promptDebug != nil {
+		result.PromptDebug = promptDebug
+	}
 
-	if err := json.NewEncoder(w).Encode(result); err != nil {
+	// Return analysis result
+	if _, err := writeAnalysis(w, h.envelope, result); err != nil {
 		h.logger.Error("Failed to encode response", err)
 	}
 
@@ -107,15 +174,83 @@ func (h *ManualWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
-func (h *ManualWebhookHandler) writeErrorResponse(w http.ResponseWriter, err error, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// analysisTimeout returns the timeout for this request: the X-Analysis-Timeout header (a Go duration
+// such as "5m" or a number of seconds) clamped to the configured bounds, or the default when absent.
+func (h *ManualWebhookHandler) analysisTimeout(r *http.Request) (time.Duration, error) {
+	value := strings.TrimSpace(r.Header.Get(AnalysisTimeoutHeader))
+	if value == "" {
+		// A zero default leaves the analysis bounded only by the request context
+		return h.config.AnalysisTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, pkgerrors.NewValidationError(fmt.Sprintf("invalid %s header %q", AnalysisTimeoutHeader, value)).
+				WithCode(pkgerrors.CodeInvalidAnalysisTimeout)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
 
-	response := map[string]string{
-		"error": err.Error(),
+	if timeout < h.config.MinAnalysisTimeout {
+		timeout = h.config.MinAnalysisTimeout
+	}
+	if h.config.MaxAnalysisTimeout > 0 && timeout > h.config.MaxAnalysisTimeout {
+		timeout = h.config.MaxAnalysisTimeout
+	}
+	return timeout, nil
+}
+
+// readDiff extracts the diff and optional collection override according to the request content type.
+// JSON bodies use the "diff" and "collection_id" fields, text/plain (and text/x-diff, text/x-patch) bodies
+// are the diff itself, and multipart uploads read the "file" part. Raw and multipart requests pass the
+// collection override as the collection_id query or form parameter.
+func (h *ManualWebhookHandler) readDiff(w http.ResponseWriter, r *http.Request) (string, string, error) {
+	body := http.MaxBytesReader(w, r.Body, h.maxBodySize)
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		// Default to JSON for requests without a content type
+		mediaType = "application/json"
 	}
 
-	if encErr := json.NewEncoder(w).Encode(response); encErr != nil {
+	switch mediaType {
+	case "text/plain", "text/x-diff", "text/x-patch", "application/octet-stream":
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return "", "", err
+		}
+		return string(raw), r.URL.Query().Get("collection_id"), nil
+
+	case "multipart/form-data":
+		r.Body = body
+		if err := r.ParseMultipartForm(h.maxBodySize); err != nil {
+			return "", "", err
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return "", "", err
+		}
+		defer file.Close()
+
+		raw, err := io.ReadAll(file)
+		if err != nil {
+			return "", "", err
+		}
+		return string(raw), r.FormValue("collection_id"), nil
+
+	default:
+		var req ManualWebhookRequest
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return "", "", err
+		}
+		return req.Diff, req.CollectionID, nil
+	}
+}
+
+func (h *ManualWebhookHandler) writeErrorResponse(w http.ResponseWriter, err error, statusCode int) {
+	if encErr := middleware.WriteErrorResponse(w, statusCode, err); encErr != nil {
 		h.logger.Error("Failed to encode error response", encErr)
 	}
 }
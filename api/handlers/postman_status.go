@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+type PostmanStatusHandler struct {
+	reporter interfaces.SyncStatusReporter
+	logger   interfaces.Logger
+}
+
+// PostmanStatusResponse lists the sync status of every collection updated since startup
+type PostmanStatusResponse struct {
+	Collections []models.SyncStatus `json:"collections"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+// NewPostmanStatusHandler creates a handler reporting collection sync status
+func NewPostmanStatusHandler(reporter interfaces.SyncStatusReporter, logger interfaces.Logger) *PostmanStatusHandler {
+	return &PostmanStatusHandler{
+		reporter: reporter,
+		logger:   logger,
+	}
+}
+
+// Handle serves GET /postman/status
+func (h *PostmanStatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	response := PostmanStatusResponse{
+		Collections: h.reporter.SyncStatuses(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode Postman status response", err)
+	}
+}
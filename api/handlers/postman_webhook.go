@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/igorsal/pr-documentator/api/middleware"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// PostmanWebhookSecretHeader carries the shared secret configured on the Postman webhook; the
+// ?token= query parameter is accepted too, since Postman can't always set custom headers
+const PostmanWebhookSecretHeader = "X-Postman-Webhook-Secret"
+
+// PostmanWebhookPayload is the part of a Postman collection-change webhook we read. Postman sends
+// the changed collection; a bare collection_id is accepted for custom senders.
+type PostmanWebhookPayload struct {
+	CollectionID string `json:"collection_id"`
+	Collection   struct {
+		Info struct {
+			PostmanID string `json:"_postman_id"`
+			UID       string `json:"uid"`
+			Name      string `json:"name"`
+			UpdatedAt string `json:"updatedAt"`
+		} `json:"info"`
+	} `json:"collection"`
+}
+
+// PostmanWebhookResponse acknowledges a collection-change event
+type PostmanWebhookResponse struct {
+	Message      string `json:"message"`
+	CollectionID string `json:"collection_id"`
+	Invalidated  int    `json:"invalidated"`
+}
+
+type PostmanWebhookHandler struct {
+	listener   interfaces.CollectionChangeListener
+	secret     string
+	invalidate bool
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+}
+
+// NewPostmanWebhookHandler creates a handler for Postman collection-change webhooks. With invalidate
+// set, the listener drops cached analyses of the changed collection.
+func NewPostmanWebhookHandler(listener interfaces.CollectionChangeListener, secret string, invalidate bool, logger interfaces.Logger, metrics interfaces.MetricsCollector) *PostmanWebhookHandler {
+	return &PostmanWebhookHandler{
+		listener:   listener,
+		secret:     secret,
+		invalidate: invalidate,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Handle serves POST /webhooks/postman
+func (h *PostmanWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		h.logger.Warn("Rejected Postman webhook with invalid secret", "remote_addr", r.RemoteAddr)
+		h.metrics.IncrementCounter("postman_webhook_events_total", map[string]string{"outcome": "unauthorized"})
+		h.writeError(w, http.StatusUnauthorized, pkgerrors.NewUnauthorizedError("invalid Postman webhook secret"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		h.metrics.IncrementCounter("postman_webhook_events_total", map[string]string{"outcome": "invalid"})
+		h.writeError(w, http.StatusBadRequest, pkgerrors.NewValidationError("failed to read request body").WithCode(pkgerrors.CodeInvalidRequestBody))
+		return
+	}
+
+	var payload PostmanWebhookPayload
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			h.metrics.IncrementCounter("postman_webhook_events_total", map[string]string{"outcome": "invalid"})
+			h.writeError(w, http.StatusBadRequest, pkgerrors.NewValidationError("invalid JSON payload").WithCode(pkgerrors.CodeInvalidRequestBody))
+			return
+		}
+	}
+
+	ids := collectionIDs(r.URL.Query().Get("collection_id"), payload)
+	if len(ids) == 0 {
+		h.metrics.IncrementCounter("postman_webhook_events_total", map[string]string{"outcome": "invalid"})
+		h.writeError(w, http.StatusBadRequest, pkgerrors.NewValidationError("payload names no collection"))
+		return
+	}
+
+	h.logger.Info("Received Postman collection change",
+		"collection_id", ids[0],
+		"collection_name", payload.Collection.Info.Name,
+		"updated_at", payload.Collection.Info.UpdatedAt,
+	)
+
+	response := PostmanWebhookResponse{Message: "logged", CollectionID: ids[0]}
+	outcome := "logged"
+	if h.invalidate {
+		// The collection may be configured by its ID or its owner-prefixed UID
+		for _, id := range ids {
+			response.Invalidated += h.listener.CollectionChanged(id)
+		}
+		response.Message = "cache invalidated"
+		outcome = "invalidated"
+	}
+	h.metrics.IncrementCounter("postman_webhook_events_total", map[string]string{"outcome": outcome})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode Postman webhook response", err)
+	}
+}
+
+// authorized compares the shared secret in constant time
+func (h *PostmanWebhookHandler) authorized(r *http.Request) bool {
+	token := r.Header.Get(PostmanWebhookSecretHeader)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return h.secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) == 1
+}
+
+// collectionIDs lists the distinct identifiers of the changed collection, most specific first
+func collectionIDs(queryID string, payload PostmanWebhookPayload) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range []string{queryID, payload.CollectionID, payload.Collection.Info.UID, payload.Collection.Info.PostmanID} {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (h *PostmanWebhookHandler) writeError(w http.ResponseWriter, status int, appErr *pkgerrors.AppError) {
+	if err := middleware.WriteErrorResponse(w, status, appErr); err != nil {
+		h.logger.Error("Failed to encode Postman webhook error response", err)
+	}
+}
@@ -4,20 +4,52 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/igorsal/pr-documentator/api/middleware"
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/validator"
 )
 
+// GitHub webhook event types, from the X-GitHub-Event header
+const (
+	GitHubEventHeader      = "X-GitHub-Event"
+	GitHubEventPullRequest = "pull_request"
+	GitHubEventPing        = "ping"
+)
+
+// ignoredGitHubEvents are sent by webhooks subscribed to more than pull requests. They are
+// acknowledged so GitHub doesn't report failed deliveries, but not analyzed.
+var ignoredGitHubEvents = map[string]bool{
+	"push":                        true,
+	"pull_request_review":         true,
+	"pull_request_review_comment": true,
+	"pull_request_review_thread":  true,
+	"issue_comment":               true,
+	"check_run":                   true,
+	"check_suite":                 true,
+	"status":                      true,
+	"workflow_run":                true,
+}
+
+// GitHubEventResponse answers webhook events that aren't analyzed
+type GitHubEventResponse struct {
+	Message string `json:"message"`
+	Event   string `json:"event,omitempty"`
+}
+
 type PRAnalyzerHandler struct {
 	analyzerService interfaces.AnalyzerService
+	envelope        string
 	logger          interfaces.Logger
 	metrics         interfaces.MetricsCollector
 }
 
-// NewPRAnalyzerHandler creates a new PR analyzer handler
-func NewPRAnalyzerHandler(analyzerService interfaces.AnalyzerService, logger interfaces.Logger, metrics interfaces.MetricsCollector) *PRAnalyzerHandler {
+// NewPRAnalyzerHandler creates a new PR analyzer handler writing results in the given envelope format
+func NewPRAnalyzerHandler(analyzerService interfaces.AnalyzerService, envelope string, logger interfaces.Logger, metrics interfaces.MetricsCollector) *PRAnalyzerHandler {
 	return &PRAnalyzerHandler{
 		analyzerService: analyzerService,
+		envelope:        envelope,
 		logger:          logger,
 		metrics:         metrics,
 	}
@@ -31,9 +63,20 @@ func (h *PRAnalyzerHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate GitHub event header
-	eventType := r.Header.Get("X-GitHub-Event")
-	if eventType != "pull_request" {
+	// Only pull_request events are analyzed; ping (sent when the webhook is created) and
+	// other known events are acknowledged
+	eventType := r.Header.Get(GitHubEventHeader)
+	switch {
+	case eventType == GitHubEventPullRequest:
+	case eventType == GitHubEventPing:
+		h.logger.Info("Received GitHub webhook ping", "hook_id", r.Header.Get("X-GitHub-Hook-ID"))
+		h.writeEventResponse(w, http.StatusOK, GitHubEventResponse{Message: "pong"})
+		return
+	case ignoredGitHubEvents[eventType]:
+		h.logger.Info("Ignoring GitHub event", "event_type", eventType)
+		h.writeEventResponse(w, http.StatusAccepted, GitHubEventResponse{Message: "event ignored", Event: eventType})
+		return
+	default:
 		h.logger.Warn("Invalid GitHub event type", "event_type", eventType)
 		http.Error(w, "Invalid event type", http.StatusBadRequest)
 		return
@@ -47,6 +90,13 @@ func (h *PRAnalyzerHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validate required fields before any processing
+	if err := validator.Struct(payload); err != nil {
+		h.logger.Warn("Invalid GitHub payload", "error", err)
+		h.writeValidationError(w, err)
+		return
+	}
+
 	h.logger.Info("Received GitHub PR webhook",
 		"pr_number", payload.PullRequest.Number,
 		"repo", payload.Repository.FullName,
@@ -55,27 +105,35 @@ func (h *PRAnalyzerHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Analyze the PR
+	payload.Source = models.PayloadSourceWebhook
 	analysisResp, err := h.analyzerService.AnalyzePR(r.Context(), payload)
 	if err != nil {
 		h.logger.Error("Failed to analyze PR", err,
 			"pr_number", payload.PullRequest.Number,
 			"repo", payload.Repository.FullName,
 		)
-		http.Error(w, "Analysis failed", http.StatusInternalServerError)
+		h.writeAnalysisError(w, err)
 		return
 	}
 
-	// Return the analysis response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	if analysisResp.PostmanUpdate.Failed() {
+		h.logger.Warn("PR analysis completed but Postman update failed",
+			"pr_number", payload.PullRequest.Number,
+			"error_type", analysisResp.PostmanUpdate.ErrorType,
+		)
+	}
 
-	if err := json.NewEncoder(w).Encode(map[string]any{
-		"status":    "success",
-		"analysis":  analysisResp,
-		"timestamp": payload.PullRequest.UpdatedAt,
-	}); err != nil {
+	// Return the analysis response
+	if _, err := writeAnalysis(w, h.envelope, analysisResp); err != nil {
 		h.logger.Error("Failed to encode analysis response", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if !analysisResp.Processed {
+		h.logger.Info("PR was not analyzed",
+			"pr_number", payload.PullRequest.Number,
+			"skip_reason", analysisResp.SkipReason,
+		)
 		return
 	}
 
@@ -87,3 +145,28 @@ func (h *PRAnalyzerHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		"postman_status", analysisResp.PostmanUpdate.Status,
 	)
 }
+
+// writeAnalysisError writes a generic error response with the status and machine-readable code of err
+func (h *PRAnalyzerHandler) writeAnalysisError(w http.ResponseWriter, err error) {
+	appErr := pkgerrors.NewInternalError("Analysis failed").WithCode(pkgerrors.CodeOf(err))
+	if encErr := middleware.WriteErrorResponse(w, pkgerrors.HTTPStatus(err), appErr); encErr != nil {
+		h.logger.Error("Failed to encode analysis error response", encErr)
+	}
+}
+
+// writeValidationError writes a 400 response listing each invalid payload field and the rule it failed
+func (h *PRAnalyzerHandler) writeValidationError(w http.ResponseWriter, err error) {
+	appErr := validator.ToAppError(err, "invalid GitHub payload")
+	if encErr := middleware.WriteErrorResponse(w, http.StatusBadRequest, appErr); encErr != nil {
+		h.logger.Error("Failed to encode validation error response", encErr)
+	}
+}
+
+// writeEventResponse acknowledges a webhook event that isn't analyzed
+func (h *PRAnalyzerHandler) writeEventResponse(w http.ResponseWriter, status int, response GitHubEventResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode GitHub event response", err)
+	}
+}
@@ -9,13 +9,18 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
+
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 )
 
-// GitHubWebhookAuth validates GitHub webhook signatures
-func GitHubWebhookAuth(secret string, logger interfaces.Logger) func(http.Handler) http.Handler {
+// GitHubWebhookAuth validates GitHub webhook signatures against the secret returned by
+// currentSecret, which is consulted per request so rotated secrets apply immediately
+func GitHubWebhookAuth(currentSecret func() string, logger interfaces.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret := currentSecret()
+
 			// Skip validation if no secret is configured
 			if secret == "" {
 				logger.Warn("GitHub webhook secret not configured, skipping signature validation")
@@ -71,12 +76,17 @@ func validateGitHubSignature(signature string, body []byte, secret string) bool
 	return hmac.Equal([]byte(signature), []byte(expectedSignature))
 }
 
-// CORSMiddleware adds CORS headers
+// defaultCORSMethods is advertised when the matched route doesn't restrict its methods
+const defaultCORSMethods = "POST, GET, OPTIONS, PUT, DELETE"
+
+// CORSMiddleware adds CORS headers. Allowed methods are those the matched route is registered
+// with, so read-only endpoints only advertise GET and OPTIONS. Routes must accept OPTIONS to
+// answer preflight requests.
 func CORSMiddleware(logger interfaces.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+			w.Header().Set("Access-Control-Allow-Methods", corsMethods(r))
 			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-GitHub-Event, X-Hub-Signature-256")
 
 			// Handle preflight requests
@@ -90,6 +100,26 @@ func CORSMiddleware(logger interfaces.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// corsMethods lists the methods of the route matching r, always including OPTIONS
+func corsMethods(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return defaultCORSMethods
+	}
+	methods, err := route.GetMethods()
+	if err != nil || len(methods) == 0 {
+		return defaultCORSMethods
+	}
+
+	allowed := make([]string, 0, len(methods)+1)
+	for _, method := range methods {
+		if method != http.MethodOptions {
+			allowed = append(allowed, method)
+		}
+	}
+	return strings.Join(append(allowed, http.MethodOptions), ", ")
+}
+
 // RecoveryMiddleware recovers from panics
 func RecoveryMiddleware(logger interfaces.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
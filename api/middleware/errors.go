@@ -43,17 +43,16 @@ type errorResponseWriter struct {
 	request *http.Request
 }
 
-// WriteError writes a structured error response
-func (erw *errorResponseWriter) WriteError(err error) {
-	var appErr *pkgerrors.AppError
-	var statusCode int
-	var errorResp ErrorResponse
-
-	// Check if it's our custom error type
-	if pkgerrors.IsAppError(err) {
-		appErr, _ = pkgerrors.AsAppError(err)
-		statusCode = appErr.StatusCode
-		errorResp = ErrorResponse{
+// Unwrap exposes the underlying writer to http.ResponseController
+func (erw *errorResponseWriter) Unwrap() http.ResponseWriter {
+	return erw.ResponseWriter
+}
+
+// NewErrorResponse builds the structured error response and status code for err.
+// Non-AppErrors are reported as a generic internal error without leaking details.
+func NewErrorResponse(err error) (int, ErrorResponse) {
+	if appErr, ok := pkgerrors.AsAppError(err); ok {
+		return pkgerrors.HTTPStatus(appErr), ErrorResponse{
 			Error: ErrorDetail{
 				Type:    string(appErr.Type),
 				Message: appErr.Message,
@@ -61,17 +60,30 @@ func (erw *errorResponseWriter) WriteError(err error) {
 				Context: appErr.Context,
 			},
 		}
-	} else {
-		// Generic error handling
-		statusCode = http.StatusInternalServerError
-		errorResp = ErrorResponse{
-			Error: ErrorDetail{
-				Type:    string(pkgerrors.ErrorTypeInternal),
-				Message: "Internal server error",
-			},
-		}
 	}
 
+	// Generic error handling
+	return pkgerrors.HTTPStatus(err), ErrorResponse{
+		Error: ErrorDetail{
+			Type:    string(pkgerrors.ErrorTypeInternal),
+			Message: "Internal server error",
+			Code:    pkgerrors.CodeInternal,
+		},
+	}
+}
+
+// WriteErrorResponse writes err as a structured error response with the given status code
+func WriteErrorResponse(w http.ResponseWriter, statusCode int, err error) error {
+	_, errorResp := NewErrorResponse(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(errorResp)
+}
+
+// WriteError writes a structured error response
+func (erw *errorResponseWriter) WriteError(err error) {
+	statusCode, errorResp := NewErrorResponse(err)
+
 	// Log the error with context
 	erw.logger.Error("Request error",
 		err,
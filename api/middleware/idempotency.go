@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const (
+	// IdempotencyKeyHeader lets non-GitHub callers supply their own key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// GitHubDeliveryHeader uniquely identifies a GitHub webhook delivery, including redeliveries
+	GitHubDeliveryHeader = "X-GitHub-Delivery"
+	// IdempotentReplayHeader is set on responses served from the idempotency cache
+	IdempotentReplayHeader = "Idempotent-Replayed"
+)
+
+// cachedResponse is a complete (200) response kept for replay
+type cachedResponse struct {
+	key         string
+	statusCode  int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// idempotencyCache is a size-bounded LRU of responses that expire after a TTL
+type idempotencyCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+}
+
+func newIdempotencyCache(ttl time.Duration, maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *idempotencyCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedResponse)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (c *idempotencyCache) put(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.expiresAt = time.Now().Add(c.ttl)
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// IdempotencyMiddleware replays the 200 response of a previous request carrying the same
+// X-GitHub-Delivery or Idempotency-Key header for ttl, keeping at most maxEntries responses.
+// Responses marked Cache-Control: no-store are never replayed.
+// Requests with ?force=true bypass the cache to deliberately re-run an analysis, and are marked with
+// models.WithForce so the analysis cache is bypassed too.
+func IdempotencyMiddleware(ttl time.Duration, maxEntries int, logger interfaces.Logger, metrics interfaces.MetricsCollector) func(http.Handler) http.Handler {
+	cache := newIdempotencyCache(ttl, maxEntries)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			force := strings.EqualFold(r.URL.Query().Get("force"), "true")
+			if force {
+				r = r.WithContext(models.WithForce(r.Context()))
+			}
+
+			key := idempotencyKey(r)
+			if ttl <= 0 || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if force {
+				logger.Info("Bypassing idempotency cache", "idempotency_key", key)
+				metrics.IncrementCounter("idempotency_requests_total", map[string]string{"result": "forced"})
+			} else if cached, ok := cache.get(key); ok {
+				logger.Info("Replaying cached response for duplicate request", "idempotency_key", key)
+				metrics.IncrementCounter("idempotency_requests_total", map[string]string{"result": "replay"})
+
+				if cached.contentType != "" {
+					w.Header().Set("Content-Type", cached.contentType)
+				}
+				w.Header().Set(IdempotentReplayHeader, "true")
+				w.WriteHeader(cached.statusCode)
+				_, _ = w.Write(cached.body)
+				return
+			} else {
+				metrics.IncrementCounter("idempotency_requests_total", map[string]string{"result": "miss"})
+			}
+
+			recorder := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// Only complete results are replayed. Failures, partial results (207) and responses marked
+			// no-store (deferred or unsettled Postman updates) stay retryable.
+			if recorder.statusCode == http.StatusOK && !strings.Contains(w.Header().Get("Cache-Control"), "no-store") {
+				cache.put(&cachedResponse{
+					key:         key,
+					statusCode:  recorder.statusCode,
+					contentType: w.Header().Get("Content-Type"),
+					body:        recorder.body.Bytes(),
+				})
+			}
+		})
+	}
+}
+
+func idempotencyKey(r *http.Request) string {
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		return key
+	}
+	return r.Header.Get(GitHubDeliveryHeader)
+}
+
+// recordingResponseWriter copies the response body while writing it through
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -50,3 +50,8 @@ func (mrw *metricsResponseWriter) WriteHeader(code int) {
 func (mrw *metricsResponseWriter) Write(b []byte) (int, error) {
 	return mrw.ResponseWriter.Write(b)
 }
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (mrw *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return mrw.ResponseWriter
+}
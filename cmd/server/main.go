@@ -18,15 +18,23 @@ import (
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/services"
 	"github.com/igorsal/pr-documentator/io/claude"
+	"github.com/igorsal/pr-documentator/io/github"
+	natsclient "github.com/igorsal/pr-documentator/io/nats"
 	"github.com/igorsal/pr-documentator/io/postman"
+	sqsclient "github.com/igorsal/pr-documentator/io/sqs"
+	"github.com/igorsal/pr-documentator/io/webhook"
+	"github.com/igorsal/pr-documentator/pkg/audit"
+	"github.com/igorsal/pr-documentator/pkg/backoff"
+	"github.com/igorsal/pr-documentator/pkg/events"
 	"github.com/igorsal/pr-documentator/pkg/logger"
 	"github.com/igorsal/pr-documentator/pkg/metrics"
 )
 
 const (
-	DefaultVersion  = "2.0.0"
-	ShutdownTimeout = 30 * time.Second
-	IdleTimeout     = 120 * time.Second
+	DefaultVersion   = "2.0.0"
+	ShutdownTimeout  = 30 * time.Second
+	IdleTimeout      = 120 * time.Second
+	PreflightTimeout = 30 * time.Second
 )
 
 // Application holds all dependencies
@@ -37,6 +45,17 @@ type Application struct {
 	claudeClient    interfaces.ClaudeClient
 	postmanClient   interfaces.PostmanClient
 	analyzerService interfaces.AnalyzerService
+	outputWebhook   *webhook.Client
+	checkRuns       *github.ChecksClient
+	audit           *audit.Logger
+	events          *events.Emitter
+	inFlight        interfaces.InFlightReporter
+	jobs            interfaces.JobReporter
+	background      interfaces.BackgroundWaiter
+	syncStatus      interfaces.SyncStatusReporter
+	toolSchema      interfaces.ToolSchemaReporter
+	collections     interfaces.CollectionChangeListener
+	circuitBreakers []interfaces.CircuitBreaker
 	server          *http.Server
 }
 
@@ -65,18 +84,96 @@ func initializeApplication() (*Application, error) {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Fail fast on malformed Claude tool definitions
+	if err := claude.ValidateToolSchemas(cfg.Claude.RequiredFields); err != nil {
+		return nil, fmt.Errorf("invalid Claude tool schema: %w", err)
+	}
+
 	// Initialize logger
 	logger := logger.NewAdapter(cfg.Logging.Level, cfg.Logging.Format)
 
 	// Initialize metrics collector
-	metrics := metrics.NewPrometheusCollector()
+	metrics := metrics.NewPrometheusCollector(cfg.Metrics.Environment)
 
 	// Initialize clients with dependencies
 	claudeClient := claude.NewClient(cfg.Claude, logger, metrics)
 	postmanClient := postman.NewClient(cfg.Postman, logger, metrics)
 
+	// Audit credential use and documentation changes, apart from operational logs
+	auditLogger, err := audit.New(cfg.Audit, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	claudeClient.SetAuditLogger(auditLogger)
+	postmanClient.SetAuditLogger(auditLogger)
+
+	if cfg.Logging.BodiesOnError {
+		claudeClient.SetBodyLogging(cfg.Logging.MaxBodyBytes)
+		postmanClient.SetBodyLogging(cfg.Logging.MaxBodyBytes)
+	}
+
+	// Optionally verify credentials before accepting any traffic
+	if cfg.Server.StartupHealthcheck {
+		if err := runPreflight(claudeClient, postmanClient, logger); err != nil {
+			return nil, fmt.Errorf("startup health check failed: %w", err)
+		}
+	}
+
+	// Structured lifecycle events for the analytics pipeline
+	eventEmitter, err := events.New(cfg.Events, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event sink: %w", err)
+	}
+
 	// Initialize services
-	analyzerService := services.NewAnalyzerService(claudeClient, postmanClient, logger, metrics)
+	analyzerService := services.NewAnalyzerService(claudeClient, postmanClient, cfg.Analyzer, logger, metrics)
+	analyzerService.SetAuditLogger(auditLogger)
+	analyzerService.SetEventEmitter(eventEmitter)
+	analyzerService.RegisterTransformer(services.NewPathNormalizationTransformer())
+	if cfg.Analyzer.RequiredPathPrefix != "" {
+		prefixTransformer, err := services.NewPathPrefixTransformer(cfg.Analyzer.RequiredPathPrefix, cfg.Analyzer.PathPrefixExceptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure path prefix: %w", err)
+		}
+		analyzerService.RegisterTransformer(prefixTransformer)
+	}
+	if len(cfg.Analyzer.PathAllowPatterns) > 0 || len(cfg.Analyzer.PathDenyPatterns) > 0 {
+		filterTransformer, err := services.NewPathFilterTransformer(cfg.Analyzer.PathAllowPatterns, cfg.Analyzer.PathDenyPatterns, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure path filters: %w", err)
+		}
+		analyzerService.RegisterTransformer(filterTransformer)
+	}
+
+	diffTransformers, err := services.NewDiffTransformers(cfg.Analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure diff transformers: %w", err)
+	}
+	for _, t := range diffTransformers {
+		analyzerService.RegisterDiffTransformer(t)
+	}
+
+	// Retry or serve cached analyses while Claude's circuit breaker is open
+	analyzerService.WatchClaudeBreaker(claudeClient.CircuitBreaker())
+	analyzerService.WatchClaudeRateLimits(claudeClient)
+
+	var outputWebhook *webhook.Client
+	if cfg.Output.URL != "" {
+		outputWebhook = webhook.NewClient(cfg.Output, logger, metrics)
+		analyzerService.RegisterNotifier(outputWebhook)
+	}
+
+	var checkRuns *github.ChecksClient
+	if cfg.GitHub.CheckRunsEnabled {
+		checkRuns = github.NewChecksClient(cfg.GitHub, logger, metrics)
+		analyzerService.RegisterNotifier(checkRuns)
+	}
+
+	// Pick up credentials rotated in the secret manager
+	if cfg.SecretStore() != nil && cfg.Secrets.RefreshInterval > 0 {
+		targets := secretTargets{claude: claudeClient, postman: postmanClient, analyzer: analyzerService, checkRuns: checkRuns}
+		go refreshSecrets(*cfg, targets, logger, metrics)
+	}
 
 	// Create application
 	app := &Application{
@@ -86,6 +183,17 @@ func initializeApplication() (*Application, error) {
 		claudeClient:    claudeClient,
 		postmanClient:   postmanClient,
 		analyzerService: analyzerService,
+		outputWebhook:   outputWebhook,
+		checkRuns:       checkRuns,
+		audit:           auditLogger,
+		events:          eventEmitter,
+		inFlight:        analyzerService,
+		jobs:            analyzerService,
+		background:      analyzerService,
+		syncStatus:      postmanClient,
+		toolSchema:      claudeClient,
+		collections:     analyzerService,
+		circuitBreakers: []interfaces.CircuitBreaker{claudeClient.CircuitBreaker(), postmanClient.CircuitBreaker()},
 	}
 
 	// Setup HTTP server
@@ -94,12 +202,32 @@ func initializeApplication() (*Application, error) {
 	return app, nil
 }
 
+// runPreflight checks Claude and Postman connectivity and credentials
+func runPreflight(claudeClient *claude.Client, postmanClient *postman.Client, logger interfaces.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), PreflightTimeout)
+	defer cancel()
+
+	logger.Info("Running startup health check")
+	if err := claudeClient.Preflight(ctx); err != nil {
+		return err
+	}
+	if err := postmanClient.Preflight(ctx); err != nil {
+		return err
+	}
+	logger.Info("Startup health check passed")
+	return nil
+}
+
 // setupServer configures the HTTP server with all routes and middleware
 func (app *Application) setupServer() {
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(app.logger, app.metrics)
-	prAnalyzerHandler := handlers.NewPRAnalyzerHandler(app.analyzerService, app.logger, app.metrics)
-	manualWebhookHandler := handlers.NewManualWebhookHandler(app.analyzerService, app.logger, app.metrics)
+	healthHandler := handlers.NewHealthHandler(app.config.Server.AdminToken, app.inFlight, app.circuitBreakers, app.logger, app.metrics)
+	prAnalyzerHandler := handlers.NewPRAnalyzerHandler(app.analyzerService, app.config.Server.ResponseEnvelope, app.logger, app.metrics)
+	jobsHandler := handlers.NewJobsHandler(app.jobs, app.logger)
+	postmanStatusHandler := handlers.NewPostmanStatusHandler(app.syncStatus, app.logger)
+	debugHandler := handlers.NewDebugHandler(app.toolSchema, app.config.Server.AdminToken, app.logger)
+	exportHandler := handlers.NewExportHandler(app.logger)
+	manualWebhookHandler := handlers.NewManualWebhookHandler(app.analyzerService, app.config.Analyzer, app.config.Server.AdminToken, app.config.Server.ResponseEnvelope, app.logger, app.metrics)
 
 	// Setup router
 	router := mux.NewRouter()
@@ -111,15 +239,33 @@ func (app *Application) setupServer() {
 	router.Use(middleware.ErrorHandlerMiddleware(app.logger))
 	router.Use(middleware.CORSMiddleware(app.logger))
 
-	// Public endpoints
-	router.HandleFunc("/health", healthHandler.Handle).Methods("GET")
-	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
-	router.HandleFunc("/manual-analyze", manualWebhookHandler.Handle).Methods("POST")
+	// Mount every route under the configured base path, if any
+	api := router
+	if app.config.Server.BasePath != "" {
+		api = router.PathPrefix(app.config.Server.BasePath).Subrouter()
+	}
+
+	// Public endpoints. OPTIONS is registered so CORS preflights match the route and only
+	// advertise its own methods.
+	api.HandleFunc("/health", healthHandler.Handle).Methods("GET", "OPTIONS")
+	api.Handle("/metrics", promhttp.Handler()).Methods("GET", "OPTIONS")
+	api.HandleFunc("/manual-analyze", manualWebhookHandler.Handle).Methods("POST", "OPTIONS")
+	api.HandleFunc("/jobs/{id}", jobsHandler.Handle).Methods("GET", "OPTIONS")
+	api.HandleFunc("/postman/status", postmanStatusHandler.Handle).Methods("GET", "OPTIONS")
+	api.HandleFunc("/debug/tool-schema", debugHandler.ToolSchema).Methods("GET", "OPTIONS")
+	api.HandleFunc("/export/asyncapi", exportHandler.AsyncAPI).Methods("POST", "OPTIONS")
+
+	// Postman collection-change webhook, authenticated by its own shared secret
+	if app.config.Postman.WebhookSecret != "" {
+		postmanWebhookHandler := handlers.NewPostmanWebhookHandler(app.collections, app.config.Postman.WebhookSecret, app.config.Postman.InvalidateOnWebhook, app.logger, app.metrics)
+		api.HandleFunc("/webhooks/postman", postmanWebhookHandler.Handle).Methods("POST", "OPTIONS")
+	}
 
 	// Protected endpoints
-	prRouter := router.PathPrefix("").Subrouter()
-	prRouter.Use(middleware.GitHubWebhookAuth(app.config.GitHub.WebhookSecret, app.logger))
-	prRouter.HandleFunc("/analyze-pr", prAnalyzerHandler.Handle).Methods("POST")
+	prRouter := api.PathPrefix("").Subrouter()
+	prRouter.Use(middleware.GitHubWebhookAuth(app.config.GitHubWebhookSecret, app.logger))
+	prRouter.Use(middleware.IdempotencyMiddleware(app.config.Server.IdempotencyTTL, app.config.Server.IdempotencyCacheSize, app.logger, app.metrics))
+	prRouter.HandleFunc("/analyze-pr", prAnalyzerHandler.Handle).Methods("POST", "OPTIONS")
 
 	// Setup server with robust configuration
 	app.server = &http.Server{
@@ -133,8 +279,82 @@ func (app *Application) setupServer() {
 	}
 }
 
-// run starts the application and handles graceful shutdown
+// run starts the configured ingest mode and handles graceful shutdown
 func (app *Application) run() error {
+	if app.config.Ingest.Mode == config.IngestModeQueue {
+		return app.runQueueConsumer()
+	}
+	return app.runHTTPServer()
+}
+
+// queueClient consumes PR events and publishes analysis results
+type queueClient interface {
+	interfaces.QueueConsumer
+	interfaces.QueuePublisher
+}
+
+// connectQueue creates the client of the configured queue backend
+func connectQueue(ctx context.Context, cfg config.QueueConfig, logger interfaces.Logger) (queueClient, error) {
+	if cfg.Backend == config.QueueBackendSQS {
+		return sqsclient.NewClient(cfg, logger)
+	}
+	return natsclient.NewClient(ctx, cfg, logger)
+}
+
+// runQueueConsumer consumes PR events from the configured queue until a shutdown signal is received
+func (app *Application) runQueueConsumer() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	queueCfg := app.config.Ingest.Queue
+	app.logger.Info("Starting queue consumer",
+		"backend", queueCfg.Backend,
+		"subject", queueCfg.Subject,
+		"output_subject", queueCfg.OutputSubject,
+	)
+
+	// Reconnect with jittered exponential backoff so replicas restarting together don't stampede the broker
+	err := backoff.Retry(ctx, backoff.DefaultPolicy, 0, nil, func(attempt int) error {
+		if attempt > 0 {
+			app.logger.Warn("Reconnecting to queue", "attempt", attempt)
+		}
+
+		client, err := connectQueue(ctx, queueCfg, app.logger)
+		if err != nil {
+			app.logger.Error("Failed to connect to queue", err, "attempt", attempt)
+			return err
+		}
+		defer client.Close()
+
+		var publisher interfaces.QueuePublisher
+		if queueCfg.OutputSubject != "" {
+			publisher = client
+		}
+
+		worker := services.NewQueueWorker(app.analyzerService, client, publisher, app.logger, app.metrics)
+		if err := worker.Run(ctx); err != nil {
+			app.logger.Error("Queue consumer failed", err, "attempt", attempt)
+			return err
+		}
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("queue consumer failed: %w", err)
+	}
+
+	// Let deferred analyses and background Postman updates settle before exiting
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := app.background.WaitBackground(shutdownCtx); err != nil {
+		app.logger.Warn("Background Postman updates still pending at shutdown", "error", err)
+	}
+
+	app.logger.Info("Queue consumer stopped")
+	return nil
+}
+
+// runHTTPServer starts the HTTPS server and handles graceful shutdown
+func (app *Application) runHTTPServer() error {
 	// Channel to capture server errors
 	serverErrors := make(chan error, 1)
 
@@ -187,6 +407,30 @@ func (app *Application) gracefulShutdown() error {
 			return
 		}
 
+		// Drop deferred analyses and let background Postman updates finish; they notify the output webhook when done
+		if err := app.background.WaitBackground(shutdownCtx); err != nil {
+			app.logger.Warn("Background Postman updates still pending at shutdown", "error", err)
+		}
+
+		// Let pending output webhook deliveries finish
+		if app.outputWebhook != nil {
+			if err := app.outputWebhook.Wait(shutdownCtx); err != nil {
+				app.logger.Warn("Output webhook deliveries still pending at shutdown", "error", err)
+			}
+		}
+		if app.checkRuns != nil {
+			if err := app.checkRuns.Wait(shutdownCtx); err != nil {
+				app.logger.Warn("GitHub Check Runs still pending at shutdown", "error", err)
+			}
+		}
+
+		if err := app.audit.Close(); err != nil {
+			app.logger.Warn("Failed to close audit log", "error", err)
+		}
+		if err := app.events.Close(); err != nil {
+			app.logger.Warn("Failed to close event sink", "error", err)
+		}
+
 		// Close other resources if needed (database connections, etc.)
 		app.logger.Info("All services shutdown successfully")
 		shutdownComplete <- nil
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/services"
+	"github.com/igorsal/pr-documentator/io/claude"
+	"github.com/igorsal/pr-documentator/io/github"
+	"github.com/igorsal/pr-documentator/io/postman"
+)
+
+// secretTargets receive rotated credentials; checkRuns is nil when Check Runs are disabled
+type secretTargets struct {
+	claude    *claude.Client
+	postman   *postman.Client
+	analyzer  *services.AnalyzerService
+	checkRuns *github.ChecksClient
+}
+
+// refreshSecrets re-reads the secret manager every Secrets.RefreshInterval and hands rotated
+// credentials to the clients. The GitHub webhook secret needs no hand-off: the webhook auth
+// middleware reads it from the store per request. A failed refresh keeps the cached secrets.
+func refreshSecrets(cfg config.Config, targets secretTargets, logger interfaces.Logger, metrics interfaces.MetricsCollector) {
+	store, interval := cfg.SecretStore(), cfg.Secrets.RefreshInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		changed, err := store.Refresh(ctx)
+		cancel()
+		if err != nil {
+			logger.Warn("Failed to refresh secrets, keeping cached values", "provider", store.Provider(), "error", err)
+			metrics.IncrementCounter("secrets_refresh_total", map[string]string{"outcome": "error"})
+			continue
+		}
+		if len(changed) == 0 {
+			metrics.IncrementCounter("secrets_refresh_total", map[string]string{"outcome": "unchanged"})
+			continue
+		}
+
+		// Secret values are never logged, only their names
+		logger.Info("Secrets rotated", "provider", store.Provider(), "secrets", changed)
+		metrics.IncrementCounter("secrets_refresh_total", map[string]string{"outcome": "rotated"})
+
+		// Secrets missing from the store keep their environment values, as at startup
+		rotated := cfg
+		rotated.ApplySecrets(store)
+		for _, name := range changed {
+			switch name {
+			case config.SecretClaudeAPIKey, config.SecretClaudeAPIKeys:
+				if keys := rotated.Claude.Keys(); len(keys) > 0 {
+					targets.claude.SetAPIKeys(keys)
+				}
+			case config.SecretPostmanAPIKey:
+				if rotated.Postman.APIKey != "" {
+					targets.postman.SetAPIKey(rotated.Postman.APIKey)
+				}
+			case config.SecretGitHubToken:
+				targets.analyzer.SetGitHubToken(rotated.Analyzer.GitHubToken)
+				if targets.checkRuns != nil {
+					targets.checkRuns.SetToken(rotated.GitHub.Token)
+				}
+			case config.SecretGitHubWebhookSecret:
+				// Read from the store by the webhook auth middleware on every request
+			default:
+				logger.Warn("Rotated secret is only applied on restart", "secret", name)
+			}
+		}
+	}
+}
@@ -2,17 +2,36 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/doctemplate"
+	"github.com/igorsal/pr-documentator/pkg/httpclient"
+	"github.com/igorsal/pr-documentator/pkg/secretstore"
 )
 
 type Config struct {
-	Server  ServerConfig
-	Claude  ClaudeConfig
-	Postman PostmanConfig
-	GitHub  GitHubConfig
-	Logging LoggingConfig
+	Server   ServerConfig
+	Claude   ClaudeConfig
+	Postman  PostmanConfig
+	GitHub   GitHubConfig
+	Analyzer AnalyzerConfig
+	Ingest   IngestConfig
+	Output   OutputWebhookConfig
+	Logging  LoggingConfig
+	Metrics  MetricsConfig
+	Audit    AuditConfig
+	Secrets  SecretsConfig
+	Events   EventsConfig
+
+	secretStore *secretstore.Store // nil with the env provider
 }
 
 type ServerConfig struct {
@@ -22,71 +41,878 @@ type ServerConfig struct {
 	WriteTimeout time.Duration
 	TLSCertFile  string
 	TLSKeyFile   string
+	// AdminToken unlocks admin features: runtime details on GET /health?verbose=true and X-Debug-Prompt
+	AdminToken string
+	// StartupHealthcheck verifies Claude and Postman credentials before serving traffic
+	StartupHealthcheck bool
+	// BasePath prefixes every route when served behind a reverse proxy subpath, e.g. "/pr-documentator"
+	BasePath string
+	// IdempotencyTTL is how long successful PR analysis responses are replayed for the same delivery (0 disables)
+	IdempotencyTTL       time.Duration
+	IdempotencyCacheSize int
+	// ResponseEnvelope is the shape of analysis results on every analyze endpoint
+	ResponseEnvelope string
 }
 
+// Response envelope formats for analysis results
+const (
+	ResponseEnvelopeWrapped = "wrapped" // {status, analysis, timestamp, postman_failed, ...}
+	ResponseEnvelopeRaw     = "raw"     // the bare analysis
+)
+
 type ClaudeConfig struct {
-	APIKey    string
-	Model     string
-	MaxTokens int
-	BaseURL   string
-	Timeout   time.Duration
+	APIKey               string
+	APIKeys              []string // optional pool used round-robin instead of APIKey
+	Model                string
+	MaxTokens            int
+	BaseURL              string
+	Timeout              time.Duration
+	Pricing              map[string]ModelPricing
+	TLS                  OutboundTLSConfig
+	NoToolUseMode        string
+	MaxContinuationTurns int
+	MaxPromptBytes       int    // larger prompts analyze a Claude summary of the diff instead (0 disables)
+	SummaryModel         string // model for the summarization pass; empty uses the analysis model
+	ChunkConcurrency     int    // diff chunks summarized at once, across all analyses
+	ChunkRetries         int    // retries of a failed chunk before it's left out of the summary
+	Prompt               PromptConfig
+	// RepoModels overrides Model per repository, keyed by "owner/repo" or "owner/*"
+	RepoModels map[string]string
+	// RequiredFields are the top-level analysis tool fields Claude must return; route lists left
+	// out of it may be omitted, e.g. deleted_routes on a purely additive PR
+	RequiredFields []string
+}
+
+// DefaultClaudeRequiredFields is the lenient required set used when CLAUDE_REQUIRED_FIELDS is unset
+var DefaultClaudeRequiredFields = []string{"summary", "confidence"}
+
+// MinPromptBytes leaves room for the instructions and collection context around the diff
+const MinPromptBytes = 20000
+
+// Keys returns the API key pool, falling back to the single APIKey when no pool is configured
+func (c ClaudeConfig) Keys() []string {
+	if len(c.APIKeys) > 0 {
+		return c.APIKeys
+	}
+	if c.APIKey != "" {
+		return []string{c.APIKey}
+	}
+	return nil
+}
+
+// ModelFor returns the model to analyze repo with
+func (c ClaudeConfig) ModelFor(repo string) string {
+	return repoOverride(c.RepoModels, c.Model, repo)
+}
+
+// repoOverride picks the override for repo, trying the exact "owner/repo" before "owner/*"
+func repoOverride[T any](overrides map[string]T, fallback T, repo string) T {
+	if value, ok := overrides[repo]; ok {
+		return value
+	}
+	if owner, _, ok := strings.Cut(repo, "/"); ok {
+		if value, ok := overrides[owner+"/*"]; ok {
+			return value
+		}
+	}
+	return fallback
+}
+
+// PromptConfig controls which PR fields are sent to Claude and what is redacted from them
+type PromptConfig struct {
+	IncludeTitle    bool
+	IncludeBody     bool
+	IncludeRepoName bool
+	// RedactPatterns are regular expressions whose matches in the diff and PR body are replaced before sending
+	RedactPatterns []string
+}
+
+// Handling modes for Claude responses that don't call the analysis tool
+const (
+	NoToolUseModeFail  = "fail"  // return an error
+	NoToolUseModeEmpty = "empty" // return an empty analysis
+	NoToolUseModeRetry = "retry" // retry once with an explicit instruction
+)
+
+// OutboundTLSConfig configures TLS verification for outbound HTTPS calls
+type OutboundTLSConfig struct {
+	CABundleFile       string
+	InsecureSkipVerify bool
+}
+
+// ModelPricing holds USD prices per million tokens for a Claude model
+type ModelPricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// DefaultClaudePricing is used for models not overridden by CLAUDE_PRICING
+var DefaultClaudePricing = map[string]ModelPricing{
+	"claude-3-haiku-20240307":    {InputPerMillion: 0.25, OutputPerMillion: 1.25},
+	"claude-3-sonnet-20240229":   {InputPerMillion: 3, OutputPerMillion: 15},
+	"claude-3-opus-20240229":     {InputPerMillion: 15, OutputPerMillion: 75},
+	"claude-3-5-sonnet-20240620": {InputPerMillion: 3, OutputPerMillion: 15},
 }
 
 type PostmanConfig struct {
-	APIKey       string
-	WorkspaceID  string
-	CollectionID string
-	BaseURL      string
-	Timeout      time.Duration
+	APIKey              string
+	WorkspaceID         string
+	CollectionID        string
+	BaseURL             string
+	Timeout             time.Duration
+	ItemNameTemplate    string
+	DescriptionFormat   string // plain keeps Claude's description, template renders Markdown from the route
+	DescriptionTemplate string // template file for the template format; empty uses the built-in one
+	UpdateMode          string
+	DeprecationMode     string
+	DeprecatedFolder    string
+	ChangelogEnabled    bool
+	ChangelogMaxEntries int
+	PathCaseSensitive   bool
+	GroupByVersion      bool
+	DeleteRemoved       bool // delete items for routes Claude reports as removed instead of deprecating them
+	// FakeExamples fills parameters without an example with realistic values, seeded by FakerSeed
+	FakeExamples bool
+	FakerSeed    int
+	// CallbacksFolder holds documented outbound webhooks, kept apart from inbound routes
+	CallbacksFolder string
+	// FolderStrategy decides which folder new items are added to
+	FolderStrategy string
+	// ValidateItems checks generated items against the Postman collection schema before writing
+	ValidateItems bool
+	// DryRun computes updates and returns them as a unified diff preview without writing the collection
+	DryRun bool
+	// WriteBatchWindow collects updates to the same collection for this long and saves them with one PUT
+	WriteBatchWindow time.Duration
+	// WriteConflictRetries re-reads and re-applies a batch this many times when the collection changed
+	// between our GET and PUT
+	WriteConflictRetries int
+	// WriteLockTimeout bounds how long an update waits for another write to the same collection;
+	// updates still waiting then return a busy status (0 waits indefinitely)
+	WriteLockTimeout time.Duration
+	// MaxResponseExamples caps the response examples per item, keeping a success and an error
+	// response first (0 = unlimited)
+	MaxResponseExamples int
+	// WebhookSecret enables the Postman collection-change webhook; InvalidateOnWebhook drops cached
+	// analyses of the changed collection so the next analysis reads fresh state
+	WebhookSecret       string
+	InvalidateOnWebhook bool
+	// Rate-limit handling: retries on 429 and proactive backoff near the quota
+	RateLimitMaxRetries   int
+	RateLimitLowWatermark int
+	RateLimitMaxWait      time.Duration
+	TLS                   OutboundTLSConfig
+}
+
+// Postman update modes
+const (
+	UpdateModeFull     = "full"     // add, modify and deprecate items
+	UpdateModeAdditive = "additive" // only add newly discovered routes
+	UpdateModeAnnotate = "annotate" // only update descriptions of existing items
+)
+
+// Postman item description formats
+const (
+	DescriptionFormatPlain    = "plain"    // Claude's description as is
+	DescriptionFormatTemplate = "template" // Markdown rendered from the route fields
+)
+
+// Deprecated route handling modes
+const (
+	DeprecationModeInline = "inline" // prefix name and description with [DEPRECATED]
+	DeprecationModeFolder = "folder" // move the item into the deprecated folder
+)
+
+// Folder strategies for new Postman items
+const (
+	FolderStrategyFlat   = "flat"   // top level of the collection
+	FolderStrategyPrefix = "prefix" // folder per first path segment, e.g. /api/v1/users -> users
+	FolderStrategyTag    = "tag"    // folder per primary tag, top level for untagged routes
+)
+
+// DefaultItemNameTemplate names Postman items as "<METHOD> <path>"
+const DefaultItemNameTemplate = "{{.Method}} {{.Path}}"
+
+// sampleRoute is rendered by the Postman templates at startup, so references to unknown fields
+// fail validation instead of every item at runtime
+var sampleRoute = models.APIRoute{
+	Method:      "GET",
+	Path:        "/api/v1/users/{id}",
+	Description: "Get a user by ID",
+	Parameters: []models.Parameter{
+		{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID", Example: "42"},
+	},
+	Headers:    []models.Header{{Name: "Authorization", Required: true, Description: "Bearer token"}},
+	Response:   map[string]any{"id": "42"},
+	Tags:       []string{"users"},
+	Version:    "v1",
+	Confidence: 0.9,
+	Responses:  map[string]models.RouteResponse{"200": {Description: "The user", Body: map[string]any{"id": "42"}}},
+	Auth:       &models.RouteAuth{Scheme: models.AuthSchemeBearer},
 }
 
 type GitHubConfig struct {
 	WebhookSecret string
+	// Token authenticates GitHub API calls (a PAT or GitHub App installation token with checks:write)
+	Token string
+	// APIURL is the REST API base for every GitHub call (checks, diffs, file contents), e.g.
+	// https://github.example.com/api/v3 for GitHub Enterprise Server
+	APIURL string
+	// CheckRunsEnabled publishes each analysis as a Check Run on the PR head commit
+	CheckRunsEnabled   bool
+	CheckRunName       string
+	CheckMinConfidence float64 // below this the check concludes "neutral" instead of "success"
+}
+
+type AnalyzerConfig struct {
+	SkipDraftPRs        bool
+	MaxDiffBytes        int
+	MinDiffBytes        int // smaller diffs are skipped without calling Claude (0 disables)
+	DiffFetchTimeout    time.Duration
+	UseDescriptionFirst bool
+	// MaxRoutes caps the routes of one analysis as a safety valve against runaway output (0, the
+	// default, disables); MaxRoutesAction truncates or rejects analyses above it
+	MaxRoutes       int
+	MaxRoutesAction string
+	// MinRouteConfidence holds back routes scored below it from Postman updates (0 disables)
+	MinRouteConfidence float64
+	// AnalysisTimeout bounds manual analyses; X-Analysis-Timeout may override it within [MinAnalysisTimeout, MaxAnalysisTimeout]
+	AnalysisTimeout    time.Duration
+	MinAnalysisTimeout time.Duration
+	MaxAnalysisTimeout time.Duration
+	// RequiredPathPrefix is added to route paths missing it, except paths matching PathPrefixExceptions (regexes)
+	RequiredPathPrefix   string
+	PathPrefixExceptions []string
+	// Routes are only documented when their path matches one of PathAllowPatterns (if any)
+	// and none of PathDenyPatterns (regexes, applied after the path prefix)
+	PathAllowPatterns []string
+	PathDenyPatterns  []string
+	// DiffTransformers are applied to the diff in order before it is sent to Claude;
+	// filter_paths drops files matching DiffExcludePaths (regexes)
+	DiffTransformers []string
+	DiffExcludePaths []string
+	// ContextCollectionIDs are extra collections whose routes are loaded as context, concurrently
+	// with the target collection, e.g. sibling services sharing an API gateway
+	ContextCollectionIDs []string
+	// PRCooldown coalesces synchronize events arriving within it of the PR's last analysis (0 disables)
+	PRCooldown time.Duration
+	// RedactSecrets replaces credentials found in diffs before analysis; FailOnSecrets rejects such diffs instead
+	RedactSecrets bool
+	FailOnSecrets bool
+	// BreakerFallback decides what happens to analyses while the Claude circuit breaker is open
+	BreakerFallback string
+	// AsyncPostmanUpdate returns analyses right after Claude answers and updates Postman in the background
+	AsyncPostmanUpdate bool
+	// Finished background updates are kept for JobRetention, at most JobMaxRecords of them, and
+	// pruned every JobCleanupInterval
+	JobRetention       time.Duration
+	JobMaxRecords      int
+	JobCleanupInterval time.Duration
+	// DiffFormat picks the GitHub media type fetched for PRs; with GitHubToken set the diff comes
+	// from the authenticated REST API instead of the public diff_url/patch_url
+	DiffFormat  string
+	GitHubToken string
+	// DiffContextLines above GitHub's 3 expands every hunk with lines from the head revision of the
+	// file (requires GitHubToken); DiffContextRepoLines overrides it per "owner/repo" or "owner/*"
+	DiffContextLines     int
+	DiffContextRepoLines map[string]int
+	// GitHubAPIURL mirrors GitHubConfig.APIURL for diff and file content requests
+	GitHubAPIURL string
+	// AnalysisCacheTTL reuses the analysis of an identical request, model and collection across all
+	// entry points for this long (0, the default, disables); ClaudeModel is part of the cache key
+	AnalysisCacheTTL  time.Duration
+	AnalysisCacheSize int
+	ClaudeModel       string
+	ClaudeRepoModels  map[string]string
+	// DefaultCollectionID mirrors PostmanConfig.CollectionID so collection change notifications
+	// reach analyses that didn't name a collection
+	DefaultCollectionID string
+	// AugmentSummary appends computed route counts, changed paths and a confidence qualifier to Claude's summary
+	AugmentSummary bool
+	// BatchConcurrency caps concurrent analyses in batch operations such as queued retries,
+	// independently of interactive requests; Claude's rate-limit headers lower it further
+	BatchConcurrency int
+}
+
+// Diff transformers available to DIFF_TRANSFORMERS
+const (
+	DiffTransformerStripComments      = "strip_comments"      // drop comment-only lines
+	DiffTransformerCollapseWhitespace = "collapse_whitespace" // collapse indentation, drop blank lines
+	DiffTransformerFilterPaths        = "filter_paths"        // drop files matching DIFF_EXCLUDE_PATHS
+)
+
+// Media types fetched for PR changes
+const (
+	DiffFormatDiff  = "diff"  // application/vnd.github.diff, a single unified diff
+	DiffFormatPatch = "patch" // application/vnd.github.patch, one mail-formatted patch per commit
+)
+
+// ClaudeModelFor returns the model repo is analyzed with, mirroring ClaudeConfig.ModelFor
+func (c AnalyzerConfig) ClaudeModelFor(repo string) string {
+	return repoOverride(c.ClaudeRepoModels, c.ClaudeModel, repo)
+}
+
+// DefaultGitHubAPIURL is the REST API base of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubDiffContextLines is the number of context lines GitHub includes around each change
+const GitHubDiffContextLines = 3
+
+// DiffContextLinesFor returns the context lines diffs of repo are expanded to
+func (c AnalyzerConfig) DiffContextLinesFor(repo string) int {
+	return repoOverride(c.DiffContextRepoLines, c.DiffContextLines, repo)
+}
+
+// Fallbacks applied while the Claude circuit breaker is open
+const (
+	BreakerFallbackFail  = "fail"  // return the error
+	BreakerFallbackQueue = "queue" // retry once the breaker recovers
+	BreakerFallbackCache = "cache" // return the PR's last known analysis
+)
+
+// Actions taken when an analysis exceeds MaxRoutes
+const (
+	MaxRoutesActionTruncate = "truncate"
+	MaxRoutesActionReject   = "reject"
+)
+
+type IngestConfig struct {
+	Mode  string
+	Queue QueueConfig
+}
+
+// Ingest modes
+const (
+	IngestModeHTTP  = "http"
+	IngestModeQueue = "queue"
+)
+
+type QueueConfig struct {
+	Backend       string
+	URL           string
+	Subject       string
+	QueueGroup    string
+	OutputSubject string
+	// BufferSize bounds the NATS messages received but not yet analyzed; further messages are
+	// dropped, as core NATS would drop them for a slow consumer anyway
+	BufferSize int
+
+	// SQS: URL and OutputSubject are queue URLs. Region defaults to the one in the queue URL host.
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// VisibilityTimeout hides a received message from other consumers; it is renewed while the
+	// message is analyzed
+	VisibilityTimeout time.Duration
+}
+
+// Queue backends
+const (
+	QueueBackendNATS = "nats"
+	QueueBackendSQS  = "sqs"
+)
+
+type OutputWebhookConfig struct {
+	URL        string
+	Secret     string
+	Timeout    time.Duration
+	MaxRetries int
 }
 
 type LoggingConfig struct {
 	Level  string
 	Format string
+	// BodiesOnError logs the redacted request and response bodies of failed Claude and Postman
+	// calls, each truncated to MaxBodyBytes
+	BodiesOnError bool
+	MaxBodyBytes  int
+}
+
+type MetricsConfig struct {
+	// Environment is attached as a constant "environment" label to every metric
+	Environment string
 }
 
+// AuditConfig selects where the append-only audit log of credential use and documentation changes goes
+type AuditConfig struct {
+	Sink      string
+	FilePath  string
+	SyslogTag string
+}
+
+// Audit log sinks
+const (
+	AuditSinkNone   = "none"
+	AuditSinkFile   = "file"
+	AuditSinkSyslog = "syslog"
+)
+
+// EventsConfig selects where structured analysis lifecycle events are published for analytics
+type EventsConfig struct {
+	Sink     string
+	FilePath string
+}
+
+// Analytics event sinks
+const (
+	EventsSinkNone   = "none"
+	EventsSinkStdout = "stdout"
+	EventsSinkFile   = "file"
+	EventsSinkKafka  = "kafka"
+)
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 
+	// GITHUB_API_URL is the former name of GITHUB_API_BASE_URL
+	githubAPIURL := strings.TrimRight(getEnvWithDefault("GITHUB_API_BASE_URL", getEnvWithDefault("GITHUB_API_URL", DefaultGitHubAPIURL)), "/")
+
+	requiredFields := getListFromEnv("CLAUDE_REQUIRED_FIELDS")
+	if len(requiredFields) == 0 {
+		requiredFields = append([]string(nil), DefaultClaudeRequiredFields...)
+	}
+
+	outboundTLS := OutboundTLSConfig{
+		CABundleFile:       getEnvWithDefault("OUTBOUND_CA_BUNDLE_FILE", ""),
+		InsecureSkipVerify: getBoolFromEnv("INSECURE_SKIP_VERIFY", false),
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Host:         getEnvWithDefault("SERVER_HOST", "0.0.0.0"),
-			Port:         getEnvWithDefault("SERVER_PORT", "8443"),
-			ReadTimeout:  getDurationFromEnv("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getDurationFromEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
-			TLSCertFile:  getEnvWithDefault("TLS_CERT_FILE", "./certs/server.crt"),
-			TLSKeyFile:   getEnvWithDefault("TLS_KEY_FILE", "./certs/server.key"),
+			Host:                 getEnvWithDefault("SERVER_HOST", "0.0.0.0"),
+			Port:                 getEnvWithDefault("SERVER_PORT", "8443"),
+			ReadTimeout:          getDurationFromEnv("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:         getDurationFromEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			TLSCertFile:          getEnvWithDefault("TLS_CERT_FILE", "./certs/server.crt"),
+			TLSKeyFile:           getEnvWithDefault("TLS_KEY_FILE", "./certs/server.key"),
+			BasePath:             normalizeBasePath(getEnvWithDefault("BASE_PATH", "")),
+			AdminToken:           getEnvWithDefault("ADMIN_TOKEN", ""),
+			StartupHealthcheck:   getBoolFromEnv("STARTUP_HEALTHCHECK", false),
+			IdempotencyTTL:       getDurationFromEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+			IdempotencyCacheSize: getIntFromEnv("IDEMPOTENCY_CACHE_SIZE", 1000),
+			ResponseEnvelope:     getEnvWithDefault("RESPONSE_ENVELOPE", ResponseEnvelopeWrapped),
 		},
 		Claude: ClaudeConfig{
-			APIKey:    getRequiredEnv("CLAUDE_API_KEY"),
-			Model:     getEnvWithDefault("CLAUDE_MODEL", "claude-3-sonnet-20240229"),
-			MaxTokens: getIntFromEnv("CLAUDE_MAX_TOKENS", 4096),
-			BaseURL:   getEnvWithDefault("CLAUDE_BASE_URL", "https://api.anthropic.com"),
-			Timeout:   getDurationFromEnv("CLAUDE_TIMEOUT", 30*time.Second),
+			APIKey:               getEnvWithDefault("CLAUDE_API_KEY", ""),
+			APIKeys:              getListFromEnv("CLAUDE_API_KEYS"),
+			Model:                getEnvWithDefault("CLAUDE_MODEL", "claude-3-sonnet-20240229"),
+			MaxTokens:            getIntFromEnv("CLAUDE_MAX_TOKENS", 4096),
+			BaseURL:              getEnvWithDefault("CLAUDE_BASE_URL", "https://api.anthropic.com"),
+			Timeout:              getDurationFromEnv("CLAUDE_TIMEOUT", 30*time.Second),
+			Pricing:              getPricingFromEnv("CLAUDE_PRICING", DefaultClaudePricing),
+			RepoModels:           getRepoModelsFromEnv("CLAUDE_REPO_MODELS"),
+			TLS:                  outboundTLS,
+			NoToolUseMode:        getEnvWithDefault("CLAUDE_NO_TOOL_USE_MODE", NoToolUseModeFail),
+			MaxContinuationTurns: getIntFromEnv("CLAUDE_MAX_CONTINUATION_TURNS", 0),
+			MaxPromptBytes:       getIntFromEnv("CLAUDE_MAX_PROMPT_BYTES", 600000),
+			SummaryModel:         getEnvWithDefault("CLAUDE_SUMMARY_MODEL", ""),
+			ChunkConcurrency:     getIntFromEnv("CLAUDE_CHUNK_CONCURRENCY", 3),
+			ChunkRetries:         getIntFromEnv("CLAUDE_CHUNK_RETRIES", 1),
+			RequiredFields:       requiredFields,
+			Prompt: PromptConfig{
+				IncludeTitle:    getBoolFromEnv("PROMPT_INCLUDE_TITLE", true),
+				IncludeBody:     getBoolFromEnv("PROMPT_INCLUDE_BODY", true),
+				IncludeRepoName: getBoolFromEnv("PROMPT_INCLUDE_REPO_NAME", true),
+				RedactPatterns:  getListFromEnv("PROMPT_REDACT_PATTERNS"),
+			},
 		},
 		Postman: PostmanConfig{
-			APIKey:       getRequiredEnv("POSTMAN_API_KEY"),
-			WorkspaceID:  getRequiredEnv("POSTMAN_WORKSPACE_ID"),
-			CollectionID: getRequiredEnv("POSTMAN_COLLECTION_ID"),
-			BaseURL:      getEnvWithDefault("POSTMAN_BASE_URL", "https://api.postman.com"),
-			Timeout:      getDurationFromEnv("POSTMAN_TIMEOUT", 30*time.Second),
+			APIKey:                getEnvWithDefault("POSTMAN_API_KEY", ""),
+			WorkspaceID:           getRequiredEnv("POSTMAN_WORKSPACE_ID"),
+			CollectionID:          getRequiredEnv("POSTMAN_COLLECTION_ID"),
+			BaseURL:               getEnvWithDefault("POSTMAN_BASE_URL", "https://api.postman.com"),
+			Timeout:               getDurationFromEnv("POSTMAN_TIMEOUT", 30*time.Second),
+			ItemNameTemplate:      getEnvWithDefault("POSTMAN_ITEM_NAME_TEMPLATE", DefaultItemNameTemplate),
+			DescriptionFormat:     getEnvWithDefault("POSTMAN_DESCRIPTION_FORMAT", DescriptionFormatPlain),
+			DescriptionTemplate:   getEnvWithDefault("POSTMAN_DESCRIPTION_TEMPLATE_FILE", ""),
+			UpdateMode:            getEnvWithDefault("POSTMAN_UPDATE_MODE", UpdateModeFull),
+			DeprecationMode:       getEnvWithDefault("POSTMAN_DEPRECATION_MODE", DeprecationModeInline),
+			DeprecatedFolder:      getEnvWithDefault("POSTMAN_DEPRECATED_FOLDER", "_deprecated"),
+			ChangelogEnabled:      getBoolFromEnv("POSTMAN_CHANGELOG_ENABLED", false),
+			ChangelogMaxEntries:   getIntFromEnv("POSTMAN_CHANGELOG_MAX_ENTRIES", 10),
+			PathCaseSensitive:     getBoolFromEnv("POSTMAN_PATH_CASE_SENSITIVE", true),
+			GroupByVersion:        getBoolFromEnv("POSTMAN_GROUP_BY_VERSION", false),
+			FakeExamples:          getBoolFromEnv("POSTMAN_FAKE_EXAMPLES", true),
+			FakerSeed:             getIntFromEnv("POSTMAN_FAKER_SEED", 42),
+			CallbacksFolder:       getEnvWithDefault("POSTMAN_CALLBACKS_FOLDER", "Webhooks"),
+			MaxResponseExamples:   getIntFromEnv("POSTMAN_MAX_RESPONSE_EXAMPLES", 0),
+			WebhookSecret:         getEnvWithDefault("POSTMAN_WEBHOOK_SECRET", ""),
+			InvalidateOnWebhook:   getBoolFromEnv("POSTMAN_WEBHOOK_INVALIDATE_CACHE", true),
+			FolderStrategy:        getEnvWithDefault("POSTMAN_FOLDER_STRATEGY", FolderStrategyFlat),
+			ValidateItems:         getBoolFromEnv("POSTMAN_VALIDATE_ITEMS", true),
+			DryRun:                getBoolFromEnv("POSTMAN_DRY_RUN", false),
+			WriteBatchWindow:      getDurationFromEnv("POSTMAN_WRITE_BATCH_WINDOW", 500*time.Millisecond),
+			WriteConflictRetries:  getIntFromEnv("POSTMAN_WRITE_CONFLICT_RETRIES", 3),
+			WriteLockTimeout:      getDurationFromEnv("POSTMAN_WRITE_LOCK_TIMEOUT", 2*time.Minute),
+			RateLimitMaxRetries:   getIntFromEnv("POSTMAN_RATE_LIMIT_MAX_RETRIES", 3),
+			RateLimitLowWatermark: getIntFromEnv("POSTMAN_RATE_LIMIT_LOW_WATERMARK", 5),
+			RateLimitMaxWait:      getDurationFromEnv("POSTMAN_RATE_LIMIT_MAX_WAIT", 60*time.Second),
+			TLS:                   outboundTLS,
 		},
 		GitHub: GitHubConfig{
-			WebhookSecret: getEnvWithDefault("GITHUB_WEBHOOK_SECRET", ""),
+			WebhookSecret:      getEnvWithDefault("GITHUB_WEBHOOK_SECRET", ""),
+			Token:              getEnvWithDefault("GITHUB_TOKEN", ""),
+			APIURL:             githubAPIURL,
+			CheckRunsEnabled:   getBoolFromEnv("GITHUB_CHECK_RUNS_ENABLED", false),
+			CheckRunName:       getEnvWithDefault("GITHUB_CHECK_RUN_NAME", "API Documentation"),
+			CheckMinConfidence: getFloatFromEnv("GITHUB_CHECK_MIN_CONFIDENCE", 0.7),
+		},
+		Analyzer: AnalyzerConfig{
+			SkipDraftPRs:         getBoolFromEnv("SKIP_DRAFT_PRS", true),
+			MaxDiffBytes:         getIntFromEnv("MAX_DIFF_BYTES", 10*1024*1024),
+			MinDiffBytes:         getIntFromEnv("MIN_DIFF_BYTES", 0),
+			DiffFetchTimeout:     getDurationFromEnv("DIFF_FETCH_TIMEOUT", 30*time.Second),
+			UseDescriptionFirst:  getBoolFromEnv("USE_DESCRIPTION_FIRST", false),
+			MaxRoutes:            getIntFromEnv("MAX_ROUTES_PER_ANALYSIS", 0),
+			MaxRoutesAction:      getEnvWithDefault("MAX_ROUTES_ACTION", MaxRoutesActionTruncate),
+			MinRouteConfidence:   getFloatFromEnv("MIN_ROUTE_CONFIDENCE", 0),
+			AnalysisTimeout:      getDurationFromEnv("ANALYSIS_TIMEOUT", 2*time.Minute),
+			MinAnalysisTimeout:   getDurationFromEnv("ANALYSIS_TIMEOUT_MIN", 10*time.Second),
+			MaxAnalysisTimeout:   getDurationFromEnv("ANALYSIS_TIMEOUT_MAX", 10*time.Minute),
+			RequiredPathPrefix:   normalizeBasePath(getEnvWithDefault("REQUIRED_PATH_PREFIX", "")),
+			PathPrefixExceptions: getListFromEnv("PATH_PREFIX_EXCEPTIONS"),
+			ContextCollectionIDs: getListFromEnv("CONTEXT_COLLECTION_IDS"),
+			PathAllowPatterns:    getListFromEnv("DOCUMENT_PATHS_ALLOW"),
+			PathDenyPatterns:     getListFromEnv("DOCUMENT_PATHS_DENY"),
+			DiffTransformers:     getListFromEnv("DIFF_TRANSFORMERS"),
+			DiffExcludePaths:     getListFromEnv("DIFF_EXCLUDE_PATHS"),
+			PRCooldown:           getDurationFromEnv("PR_ANALYSIS_COOLDOWN", 0),
+			RedactSecrets:        getBoolFromEnv("REDACT_SECRETS", true),
+			FailOnSecrets:        getBoolFromEnv("FAIL_ON_SECRETS", false),
+			BreakerFallback:      getEnvWithDefault("CLAUDE_BREAKER_FALLBACK", BreakerFallbackFail),
+			AsyncPostmanUpdate:   getBoolFromEnv("ASYNC_POSTMAN_UPDATE", false),
+			JobRetention:         getDurationFromEnv("JOB_RETENTION", time.Hour),
+			JobMaxRecords:        getIntFromEnv("JOB_MAX_RECORDS", 1000),
+			JobCleanupInterval:   getDurationFromEnv("JOB_CLEANUP_INTERVAL", 5*time.Minute),
+			DiffFormat:           getEnvWithDefault("DIFF_FORMAT", DiffFormatDiff),
+			GitHubToken:          getEnvWithDefault("GITHUB_TOKEN", ""),
+			DiffContextLines:     getIntFromEnv("DIFF_CONTEXT_LINES", GitHubDiffContextLines),
+			DiffContextRepoLines: getRepoIntsFromEnv("DIFF_CONTEXT_LINES_REPOS"),
+			GitHubAPIURL:         githubAPIURL,
+			AnalysisCacheTTL:     getDurationFromEnv("ANALYSIS_CACHE_TTL", 0),
+			AnalysisCacheSize:    getIntFromEnv("ANALYSIS_CACHE_SIZE", 500),
+			AugmentSummary:       getBoolFromEnv("AUGMENT_SUMMARY", true),
+			BatchConcurrency:     getIntFromEnv("BATCH_CONCURRENCY", 2),
+		},
+		Ingest: IngestConfig{
+			Mode: getEnvWithDefault("INGEST_MODE", IngestModeHTTP),
+			Queue: QueueConfig{
+				Backend:       getEnvWithDefault("QUEUE_BACKEND", QueueBackendNATS),
+				URL:           getEnvWithDefault("QUEUE_URL", "nats://localhost:4222"),
+				Subject:       getEnvWithDefault("QUEUE_SUBJECT", "github.pull_request"),
+				QueueGroup:    getEnvWithDefault("QUEUE_GROUP", "pr-documentator"),
+				OutputSubject: getEnvWithDefault("QUEUE_OUTPUT_SUBJECT", ""),
+				BufferSize:    getIntFromEnv("QUEUE_BUFFER_SIZE", 100),
+
+				Region:            getEnvWithDefault("AWS_REGION", ""),
+				AccessKeyID:       getEnvWithDefault("AWS_ACCESS_KEY_ID", ""),
+				SecretAccessKey:   getEnvWithDefault("AWS_SECRET_ACCESS_KEY", ""),
+				SessionToken:      getEnvWithDefault("AWS_SESSION_TOKEN", ""),
+				VisibilityTimeout: getDurationFromEnv("QUEUE_VISIBILITY_TIMEOUT", 5*time.Minute),
+			},
+		},
+		Output: OutputWebhookConfig{
+			URL:        getEnvWithDefault("OUTPUT_WEBHOOK_URL", ""),
+			Secret:     getEnvWithDefault("OUTPUT_WEBHOOK_SECRET", ""),
+			Timeout:    getDurationFromEnv("OUTPUT_WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxRetries: getIntFromEnv("OUTPUT_WEBHOOK_MAX_RETRIES", 3),
 		},
 		Logging: LoggingConfig{
-			Level:  getEnvWithDefault("LOG_LEVEL", "info"),
-			Format: getEnvWithDefault("LOG_FORMAT", "json"),
+			Level:         getEnvWithDefault("LOG_LEVEL", "info"),
+			Format:        getEnvWithDefault("LOG_FORMAT", "json"),
+			BodiesOnError: getBoolFromEnv("LOG_BODIES_ON_ERROR", false),
+			MaxBodyBytes:  getIntFromEnv("LOG_BODIES_MAX_BYTES", 4096),
+		},
+		Metrics: MetricsConfig{
+			Environment: getEnvWithDefault("ENVIRONMENT", "development"),
+		},
+		Audit: AuditConfig{
+			Sink:      getEnvWithDefault("AUDIT_SINK", AuditSinkNone),
+			FilePath:  getEnvWithDefault("AUDIT_FILE", "./audit.log"),
+			SyslogTag: getEnvWithDefault("AUDIT_SYSLOG_TAG", "pr-documentator-audit"),
+		},
+		Events: EventsConfig{
+			Sink:     getEnvWithDefault("EVENTS_SINK", EventsSinkNone),
+			FilePath: getEnvWithDefault("EVENTS_FILE", "./events.jsonl"),
+		},
+		Secrets: SecretsConfig{
+			Provider:        getEnvWithDefault("SECRETS_PROVIDER", SecretsProviderEnv),
+			VaultAddr:       getEnvWithDefault("VAULT_ADDR", ""),
+			VaultToken:      getEnvWithDefault("VAULT_TOKEN", ""),
+			VaultNamespace:  getEnvWithDefault("VAULT_NAMESPACE", ""),
+			Path:            getEnvWithDefault("SECRETS_PATH", ""),
+			Timeout:         getDurationFromEnv("SECRETS_TIMEOUT", 10*time.Second),
+			RefreshInterval: getDurationFromEnv("SECRETS_REFRESH_INTERVAL", 5*time.Minute),
+			TLS:             outboundTLS,
 		},
 	}
 
+	cfg.Analyzer.ClaudeModel = cfg.Claude.Model
+	cfg.Analyzer.ClaudeRepoModels = cfg.Claude.RepoModels
+	cfg.Analyzer.DefaultCollectionID = cfg.Postman.CollectionID
+
+	// Secrets from the secret manager take precedence over the environment
+	if err := cfg.loadSecrets(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// validate checks configuration values that can't be verified by type alone
+func (c *Config) validate() error {
+	if c.Server.IdempotencyTTL < 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL must not be negative")
+	}
+	if c.Server.IdempotencyCacheSize <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_CACHE_SIZE must be positive")
+	}
+	if c.Analyzer.BatchConcurrency <= 0 {
+		return fmt.Errorf("BATCH_CONCURRENCY must be positive")
+	}
+	if c.Analyzer.AnalysisCacheTTL < 0 {
+		return fmt.Errorf("ANALYSIS_CACHE_TTL must not be negative")
+	}
+	if c.Analyzer.JobRetention <= 0 {
+		return fmt.Errorf("JOB_RETENTION must be positive")
+	}
+	if c.Analyzer.JobMaxRecords < 0 {
+		return fmt.Errorf("JOB_MAX_RECORDS must not be negative")
+	}
+	if c.Analyzer.JobCleanupInterval < 0 {
+		return fmt.Errorf("JOB_CLEANUP_INTERVAL must not be negative")
+	}
+
+	if c.Logging.BodiesOnError && c.Logging.MaxBodyBytes <= 0 {
+		return fmt.Errorf("LOG_BODIES_MAX_BYTES must be positive when LOG_BODIES_ON_ERROR is enabled")
+	}
+	if c.Postman.WriteLockTimeout < 0 {
+		return fmt.Errorf("POSTMAN_WRITE_LOCK_TIMEOUT must not be negative")
+	}
+	if c.Postman.MaxResponseExamples < 0 {
+		return fmt.Errorf("POSTMAN_MAX_RESPONSE_EXAMPLES must not be negative")
+	}
+	if c.Analyzer.AnalysisCacheSize <= 0 {
+		return fmt.Errorf("ANALYSIS_CACHE_SIZE must be positive")
+	}
+	if u, err := url.Parse(c.GitHub.APIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid GITHUB_API_BASE_URL %q: must be an absolute http(s) URL", c.GitHub.APIURL)
+	}
+	if c.GitHub.CheckRunsEnabled && c.GitHub.Token == "" {
+		return fmt.Errorf("GITHUB_CHECK_RUNS_ENABLED requires GITHUB_TOKEN")
+	}
+	if len(c.Claude.Keys()) == 0 {
+		return fmt.Errorf("CLAUDE_API_KEY or CLAUDE_API_KEYS must be set")
+	}
+	if c.Postman.APIKey == "" {
+		return fmt.Errorf("POSTMAN_API_KEY must be set")
+	}
+	nameTemplate, err := template.New("item_name").Parse(c.Postman.ItemNameTemplate)
+	if err == nil {
+		err = nameTemplate.Execute(io.Discard, sampleRoute)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid POSTMAN_ITEM_NAME_TEMPLATE: %w", err)
+	}
+	if c.Claude.ChunkConcurrency <= 0 {
+		return fmt.Errorf("CLAUDE_CHUNK_CONCURRENCY must be positive")
+	}
+	if c.Claude.ChunkRetries < 0 {
+		return fmt.Errorf("CLAUDE_CHUNK_RETRIES must not be negative")
+	}
+
+	switch c.Claude.NoToolUseMode {
+	case NoToolUseModeFail, NoToolUseModeEmpty, NoToolUseModeRetry:
+	default:
+		return fmt.Errorf("invalid CLAUDE_NO_TOOL_USE_MODE %q: must be one of %s, %s, %s",
+			c.Claude.NoToolUseMode, NoToolUseModeFail, NoToolUseModeEmpty, NoToolUseModeRetry)
+	}
+	switch c.Postman.UpdateMode {
+	case UpdateModeFull, UpdateModeAdditive, UpdateModeAnnotate:
+	default:
+		return fmt.Errorf("invalid POSTMAN_UPDATE_MODE %q: must be one of %s, %s, %s",
+			c.Postman.UpdateMode, UpdateModeFull, UpdateModeAdditive, UpdateModeAnnotate)
+	}
+	switch c.Postman.DescriptionFormat {
+	case DescriptionFormatPlain:
+	case DescriptionFormatTemplate:
+		descTemplate, err := doctemplate.Load(c.Postman.DescriptionTemplate)
+		if err == nil {
+			_, err = doctemplate.Render(descTemplate, sampleRoute)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid POSTMAN_DESCRIPTION_TEMPLATE_FILE: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid POSTMAN_DESCRIPTION_FORMAT %q: must be one of %s, %s",
+			c.Postman.DescriptionFormat, DescriptionFormatPlain, DescriptionFormatTemplate)
+	}
+	switch c.Postman.DeprecationMode {
+	case DeprecationModeInline, DeprecationModeFolder:
+	default:
+		return fmt.Errorf("invalid POSTMAN_DEPRECATION_MODE %q: must be one of %s, %s",
+			c.Postman.DeprecationMode, DeprecationModeInline, DeprecationModeFolder)
+	}
+	switch c.Server.ResponseEnvelope {
+	case ResponseEnvelopeWrapped, ResponseEnvelopeRaw:
+	default:
+		return fmt.Errorf("RESPONSE_ENVELOPE must be %q or %q, got %q", ResponseEnvelopeWrapped, ResponseEnvelopeRaw, c.Server.ResponseEnvelope)
+	}
+
+	switch c.Postman.FolderStrategy {
+	case FolderStrategyFlat, FolderStrategyPrefix, FolderStrategyTag:
+	default:
+		return fmt.Errorf("POSTMAN_FOLDER_STRATEGY must be one of %q, %q or %q, got %q",
+			FolderStrategyFlat, FolderStrategyPrefix, FolderStrategyTag, c.Postman.FolderStrategy)
+	}
+
+	switch c.Analyzer.BreakerFallback {
+	case BreakerFallbackFail, BreakerFallbackQueue, BreakerFallbackCache:
+	default:
+		return fmt.Errorf("CLAUDE_BREAKER_FALLBACK must be one of %q, %q or %q, got %q",
+			BreakerFallbackFail, BreakerFallbackQueue, BreakerFallbackCache, c.Analyzer.BreakerFallback)
+	}
+
+	if c.Analyzer.DiffContextLines < 0 {
+		return fmt.Errorf("DIFF_CONTEXT_LINES must not be negative")
+	}
+	for repo, lines := range c.Analyzer.DiffContextRepoLines {
+		if lines < 0 {
+			return fmt.Errorf("DIFF_CONTEXT_LINES_REPOS entry %q must not be negative", repo)
+		}
+	}
+
+	if c.Analyzer.MinDiffBytes < 0 {
+		return fmt.Errorf("MIN_DIFF_BYTES must not be negative")
+	}
+	if c.Analyzer.MaxDiffBytes > 0 && c.Analyzer.MinDiffBytes > c.Analyzer.MaxDiffBytes {
+		return fmt.Errorf("MIN_DIFF_BYTES (%d) must not exceed MAX_DIFF_BYTES (%d)", c.Analyzer.MinDiffBytes, c.Analyzer.MaxDiffBytes)
+	}
+
+	if c.Claude.MaxPromptBytes != 0 && c.Claude.MaxPromptBytes < MinPromptBytes {
+		return fmt.Errorf("CLAUDE_MAX_PROMPT_BYTES must be 0 (disabled) or at least %d, got %d", MinPromptBytes, c.Claude.MaxPromptBytes)
+	}
+
+	if c.Postman.WriteBatchWindow < 0 {
+		return fmt.Errorf("POSTMAN_WRITE_BATCH_WINDOW must not be negative")
+	}
+	if c.Postman.WriteConflictRetries < 0 {
+		return fmt.Errorf("POSTMAN_WRITE_CONFLICT_RETRIES must not be negative")
+	}
+
+	switch c.Audit.Sink {
+	case AuditSinkNone, AuditSinkFile, AuditSinkSyslog:
+	default:
+		return fmt.Errorf("AUDIT_SINK must be one of %q, %q or %q, got %q",
+			AuditSinkNone, AuditSinkFile, AuditSinkSyslog, c.Audit.Sink)
+	}
+
+	switch c.Events.Sink {
+	case EventsSinkNone, EventsSinkStdout, EventsSinkFile:
+	case EventsSinkKafka:
+		return fmt.Errorf("EVENTS_SINK %q is not supported by this build, use %s or %s", c.Events.Sink, EventsSinkStdout, EventsSinkFile)
+	default:
+		return fmt.Errorf("EVENTS_SINK must be one of %q, %q or %q, got %q",
+			EventsSinkNone, EventsSinkStdout, EventsSinkFile, c.Events.Sink)
+	}
+
+	switch c.Analyzer.DiffFormat {
+	case DiffFormatDiff, DiffFormatPatch:
+	default:
+		return fmt.Errorf("DIFF_FORMAT must be one of %q or %q, got %q",
+			DiffFormatDiff, DiffFormatPatch, c.Analyzer.DiffFormat)
+	}
+
+	switch c.Analyzer.MaxRoutesAction {
+	case MaxRoutesActionTruncate, MaxRoutesActionReject:
+	default:
+		return fmt.Errorf("invalid MAX_ROUTES_ACTION %q: must be one of %s, %s",
+			c.Analyzer.MaxRoutesAction, MaxRoutesActionTruncate, MaxRoutesActionReject)
+	}
+	// A zero ANALYSIS_TIMEOUT_MAX leaves X-Analysis-Timeout without a ceiling
+	if c.Analyzer.MaxAnalysisTimeout > 0 && c.Analyzer.MinAnalysisTimeout > c.Analyzer.MaxAnalysisTimeout {
+		return fmt.Errorf("ANALYSIS_TIMEOUT_MIN (%s) must not exceed ANALYSIS_TIMEOUT_MAX (%s)",
+			c.Analyzer.MinAnalysisTimeout, c.Analyzer.MaxAnalysisTimeout)
+	}
+	switch c.Ingest.Mode {
+	case IngestModeHTTP:
+	case IngestModeQueue:
+		switch c.Ingest.Queue.Backend {
+		case QueueBackendNATS:
+			if c.Ingest.Queue.BufferSize <= 0 {
+				return fmt.Errorf("QUEUE_BUFFER_SIZE must be positive")
+			}
+		case QueueBackendSQS:
+			if u, err := url.Parse(c.Ingest.Queue.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid QUEUE_URL %q: must be an SQS queue URL", c.Ingest.Queue.URL)
+			}
+			if c.Ingest.Queue.AccessKeyID == "" || c.Ingest.Queue.SecretAccessKey == "" {
+				return fmt.Errorf("QUEUE_BACKEND %s requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", QueueBackendSQS)
+			}
+			if c.Ingest.Queue.VisibilityTimeout < 2*time.Second || c.Ingest.Queue.VisibilityTimeout > 12*time.Hour {
+				return fmt.Errorf("QUEUE_VISIBILITY_TIMEOUT must be between 2s and 12h")
+			}
+		default:
+			return fmt.Errorf("invalid QUEUE_BACKEND %q", c.Ingest.Queue.Backend)
+		}
+	default:
+		return fmt.Errorf("invalid INGEST_MODE %q: must be one of %s, %s", c.Ingest.Mode, IngestModeHTTP, IngestModeQueue)
+	}
+	for _, pattern := range c.Analyzer.PathPrefixExceptions {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid PATH_PREFIX_EXCEPTIONS entry %q: %w", pattern, err)
+		}
+	}
+	for _, name := range c.Analyzer.DiffTransformers {
+		switch name {
+		case DiffTransformerStripComments, DiffTransformerCollapseWhitespace, DiffTransformerFilterPaths:
+		default:
+			return fmt.Errorf("invalid DIFF_TRANSFORMERS entry %q: must be one of %s, %s, %s", name,
+				DiffTransformerStripComments, DiffTransformerCollapseWhitespace, DiffTransformerFilterPaths)
+		}
+	}
+	for _, pattern := range c.Analyzer.DiffExcludePaths {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid DIFF_EXCLUDE_PATHS entry %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range c.Analyzer.PathAllowPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid DOCUMENT_PATHS_ALLOW entry %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range c.Analyzer.PathDenyPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid DOCUMENT_PATHS_DENY entry %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range c.Claude.Prompt.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid PROMPT_REDACT_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+	if c.Claude.TLS.CABundleFile != "" {
+		if _, err := httpclient.LoadCABundle(c.Claude.TLS.CABundleFile); err != nil {
+			return fmt.Errorf("invalid OUTBOUND_CA_BUNDLE_FILE: %w", err)
+		}
+	}
+	return nil
+}
+
+// normalizeBasePath (also used for path prefixes) turns "pr-documentator/" or "/pr-documentator/" into "/pr-documentator", and "/" into ""
+func normalizeBasePath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
 func getRequiredEnv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -111,6 +937,90 @@ func getIntFromEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getListFromEnv splits a comma-separated variable, dropping empty entries
+func getListFromEnv(key string) []string {
+	return splitList(os.Getenv(key))
+}
+
+func getFloatFromEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getBoolFromEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getPricingFromEnv parses "model=input:output,..." (USD per million tokens) on top of the defaults
+func getPricingFromEnv(key string, defaults map[string]ModelPricing) map[string]ModelPricing {
+	pricing := make(map[string]ModelPricing, len(defaults))
+	for model, price := range defaults {
+		pricing[model] = price
+	}
+
+	value := os.Getenv(key)
+	if value == "" {
+		return pricing
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		model, prices, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		input, output, ok := strings.Cut(prices, ":")
+		if !ok {
+			continue
+		}
+		inputPrice, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			continue
+		}
+		outputPrice, err := strconv.ParseFloat(output, 64)
+		if err != nil {
+			continue
+		}
+		pricing[model] = ModelPricing{InputPerMillion: inputPrice, OutputPerMillion: outputPrice}
+	}
+
+	return pricing
+}
+
+// getRepoModelsFromEnv parses "owner/repo=model,owner/*=model"
+func getRepoModelsFromEnv(key string) map[string]string {
+	models := make(map[string]string)
+	for _, entry := range getListFromEnv(key) {
+		repo, model, ok := strings.Cut(entry, "=")
+		repo, model = strings.TrimSpace(repo), strings.TrimSpace(model)
+		if !ok || repo == "" || model == "" {
+			continue
+		}
+		models[repo] = model
+	}
+	return models
+}
+
+// getRepoIntsFromEnv parses "owner/repo=10,owner/*=6" like getRepoModelsFromEnv, dropping
+// entries that aren't integers
+func getRepoIntsFromEnv(key string) map[string]int {
+	values := make(map[string]int)
+	for repo, value := range getRepoModelsFromEnv(key) {
+		if n, err := strconv.Atoi(value); err == nil {
+			values[repo] = n
+		}
+	}
+	return values
+}
+
 func getDurationFromEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
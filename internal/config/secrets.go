@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/igorsal/pr-documentator/pkg/httpclient"
+	"github.com/igorsal/pr-documentator/pkg/secretstore"
+)
+
+// SecretsConfig selects where credentials come from. Secrets are named after the environment
+// variables they replace; anything the secret manager doesn't hold is read from the environment.
+type SecretsConfig struct {
+	Provider       string
+	VaultAddr      string
+	VaultToken     string
+	VaultNamespace string
+	Path           string // secret path, e.g. secret/data/pr-documentator for Vault KV v2
+	Timeout        time.Duration
+	// RefreshInterval re-reads the secrets so rotated credentials are picked up (0 disables)
+	RefreshInterval time.Duration
+	TLS             OutboundTLSConfig
+}
+
+// Secret providers
+const (
+	SecretsProviderEnv   = "env"
+	SecretsProviderVault = "vault"
+	SecretsProviderAWS   = "aws"
+	SecretsProviderGCP   = "gcp"
+)
+
+// Secrets read from the secret manager
+const (
+	SecretClaudeAPIKey        = "CLAUDE_API_KEY"
+	SecretClaudeAPIKeys       = "CLAUDE_API_KEYS"
+	SecretPostmanAPIKey       = "POSTMAN_API_KEY"
+	SecretGitHubToken         = "GITHUB_TOKEN"
+	SecretGitHubWebhookSecret = "GITHUB_WEBHOOK_SECRET"
+)
+
+// SecretStore returns the secrets loaded from the secret manager, nil with the env provider
+func (c *Config) SecretStore() *secretstore.Store {
+	return c.secretStore
+}
+
+// loadSecrets reads credentials from the configured secret manager and applies them
+func (c *Config) loadSecrets() error {
+	switch c.Secrets.Provider {
+	case SecretsProviderEnv:
+		return nil
+	case SecretsProviderVault:
+	case SecretsProviderAWS, SecretsProviderGCP:
+		return fmt.Errorf("SECRETS_PROVIDER %q is not supported by this build, use %s", c.Secrets.Provider, SecretsProviderVault)
+	default:
+		return fmt.Errorf("invalid SECRETS_PROVIDER %q: must be one of %s, %s", c.Secrets.Provider, SecretsProviderEnv, SecretsProviderVault)
+	}
+
+	if c.Secrets.VaultAddr == "" || c.Secrets.VaultToken == "" || c.Secrets.Path == "" {
+		return fmt.Errorf("SECRETS_PROVIDER=%s requires VAULT_ADDR, VAULT_TOKEN and SECRETS_PATH", SecretsProviderVault)
+	}
+	httpClient, err := httpclient.New(httpclient.Options{
+		Timeout:            c.Secrets.Timeout,
+		CABundleFile:       c.Secrets.TLS.CABundleFile,
+		InsecureSkipVerify: c.Secrets.TLS.InsecureSkipVerify,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure secret manager client: %w", err)
+	}
+	store := secretstore.New(secretstore.NewVaultProvider(c.Secrets.VaultAddr, c.Secrets.VaultToken, c.Secrets.VaultNamespace, c.Secrets.Path, httpClient))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Secrets.Timeout)
+	defer cancel()
+	if _, err := store.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to load secrets from %s: %w", store.Provider(), err)
+	}
+
+	c.ApplySecrets(store)
+	c.secretStore = store
+	return nil
+}
+
+// ApplySecrets overrides credentials with the ones held by store
+func (c *Config) ApplySecrets(store *secretstore.Store) {
+	if key := store.Get(SecretClaudeAPIKey); key != "" {
+		c.Claude.APIKey = key
+	}
+	if keys := store.Get(SecretClaudeAPIKeys); keys != "" {
+		c.Claude.APIKeys = splitList(keys)
+	}
+	if key := store.Get(SecretPostmanAPIKey); key != "" {
+		c.Postman.APIKey = key
+	}
+	if token := store.Get(SecretGitHubToken); token != "" {
+		c.GitHub.Token = token
+		c.Analyzer.GitHubToken = token
+	}
+	if secret := store.Get(SecretGitHubWebhookSecret); secret != "" {
+		c.GitHub.WebhookSecret = secret
+	}
+}
+
+// GitHubWebhookSecret returns the GitHub webhook secret, read from the secret manager on every
+// call so a rotated secret is enforced without a restart
+func (c *Config) GitHubWebhookSecret() string {
+	if c.secretStore != nil {
+		if secret := c.secretStore.Get(SecretGitHubWebhookSecret); secret != "" {
+			return secret
+		}
+	}
+	return c.GitHub.WebhookSecret
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -2,6 +2,8 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/igorsal/pr-documentator/internal/models"
 )
@@ -9,12 +11,13 @@ import (
 // ClaudeClient defines the interface for Claude AI integration
 type ClaudeClient interface {
 	AnalyzePR(ctx context.Context, req models.AnalysisRequest) (*models.AnalysisResponse, error)
+	TriageDescription(ctx context.Context, req models.AnalysisRequest) (*models.DescriptionTriage, error)
 }
 
 // PostmanClient defines the interface for Postman integration
 type PostmanClient interface {
-	UpdateCollection(ctx context.Context, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error)
-	GetCollection(ctx context.Context) (*models.PostmanCollection, error)
+	UpdateCollection(ctx context.Context, collectionID string, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error)
+	GetCollection(ctx context.Context, collectionID string) (*models.PostmanCollection, error)
 }
 
 // AnalyzerService defines the interface for PR analysis orchestration
@@ -22,6 +25,75 @@ type AnalyzerService interface {
 	AnalyzePR(ctx context.Context, payload models.GitHubPRPayload) (*models.AnalysisResponse, error)
 }
 
+// InFlightReporter reports how many analyses are currently running
+type InFlightReporter interface {
+	InFlight() int64
+}
+
+// JobReporter looks up background Postman updates
+type JobReporter interface {
+	PostmanJob(id string) (*models.PostmanJob, bool)
+}
+
+// BackgroundWaiter waits for work dispatched in the background to finish
+type BackgroundWaiter interface {
+	WaitBackground(ctx context.Context) error
+}
+
+// SyncStatusReporter reports how current each Postman collection is
+type SyncStatusReporter interface {
+	SyncStatuses() []models.SyncStatus
+}
+
+// CollectionChangeListener is told when a Postman collection changed outside this service and
+// reports how many cached analyses it dropped
+type CollectionChangeListener interface {
+	CollectionChanged(collectionID string) int
+}
+
+// EventEmitter publishes structured analysis lifecycle events for analytics
+type EventEmitter interface {
+	Emit(event models.Event)
+}
+
+// AuditLogger records credential use and documentation changes to the audit log
+type AuditLogger interface {
+	Record(event models.AuditEvent)
+}
+
+// ToolSchemaReporter exposes the tool schema sent to Claude, serialized as in requests
+type ToolSchemaReporter interface {
+	ToolSchema(includeSystemPrompt bool) (json.RawMessage, error)
+}
+
+// ResponseTransformer defines a post-analysis hook applied before the Postman update
+type ResponseTransformer interface {
+	Transform(ctx context.Context, resp *models.AnalysisResponse) (*models.AnalysisResponse, error)
+}
+
+// DiffTransformer defines a pre-analysis step rewriting the diff before it is sent to Claude
+type DiffTransformer interface {
+	Name() string
+	TransformDiff(ctx context.Context, diff string) (string, error)
+}
+
+// ResultNotifier defines the interface for delivering completed analyses to external systems.
+// Implementations must not block the caller.
+type ResultNotifier interface {
+	Notify(ctx context.Context, resp *models.AnalysisResponse)
+}
+
+// QueueConsumer defines the interface for consuming events from a message queue
+type QueueConsumer interface {
+	Consume(ctx context.Context, handler func(ctx context.Context, data []byte) error) error
+	Close() error
+}
+
+// QueuePublisher defines the interface for publishing results to a message queue
+type QueuePublisher interface {
+	Publish(ctx context.Context, data []byte) error
+}
+
 // Logger defines the logging interface
 type Logger interface {
 	Debug(msg string, fields ...any)
@@ -34,6 +106,7 @@ type Logger interface {
 // MetricsCollector defines the interface for collecting metrics
 type MetricsCollector interface {
 	IncrementCounter(name string, labels map[string]string)
+	AddCounter(name string, value float64, labels map[string]string)
 	RecordDuration(name string, duration float64, labels map[string]string)
 	SetGauge(name string, value float64, labels map[string]string)
 }
@@ -45,6 +118,16 @@ type CircuitBreaker interface {
 	State() string
 }
 
+// StateChangeNotifier is implemented by circuit breakers that report their state transitions
+type StateChangeNotifier interface {
+	OnStateChange(fn func(from, to string))
+}
+
+// RateLimitNotifier is implemented by clients that report the upstream request quota
+type RateLimitNotifier interface {
+	OnRateLimit(fn func(remaining int, reset time.Time))
+}
+
 // HTTPClient defines the interface for HTTP operations
 type HTTPClient interface {
 	Get(ctx context.Context, url string) (*HTTPResponse, error)
@@ -1,11 +1,30 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/pkg/diffparse"
+)
+
 // AnalysisRequest represents the request to analyze a PR
 type AnalysisRequest struct {
-	PullRequest    PullRequest       `json:"pull_request"`
-	Repository     Repository        `json:"repository"`
-	Diff           string            `json:"diff,omitempty"`
-	ExistingRoutes []ExistingRoute   `json:"existing_routes,omitempty"`
+	PullRequest     PullRequest            `json:"pull_request"`
+	Repository      Repository             `json:"repository"`
+	Diff            string                 `json:"diff,omitempty"`
+	ExistingRoutes  []ExistingRoute        `json:"existing_routes,omitempty"`
+	IntendedChanges []string               `json:"intended_changes,omitempty"`
+	RenamedFiles    []diffparse.FileRename `json:"renamed_files,omitempty"`
+	// DeclarativeFiles are OpenAPI, Terraform, serverless or CloudFormation files in the diff
+	DeclarativeFiles []diffparse.DeclarativeFile `json:"declarative_files,omitempty"`
+}
+
+// DescriptionTriage represents the API intent extracted from the PR title and description
+type DescriptionTriage struct {
+	HasAPIChanges   bool     `json:"has_api_changes"`
+	IntendedChanges []string `json:"intended_changes"`
+	Summary         string   `json:"summary"`
 }
 
 // ExistingRoute represents a route already documented in the collection
@@ -22,9 +41,66 @@ type AnalysisResponse struct {
 	NewRoutes      []APIRoute    `json:"new_routes"`
 	ModifiedRoutes []APIRoute    `json:"modified_routes"`
 	DeletedRoutes  []APIRoute    `json:"deleted_routes"`
+	Callbacks      []Callback    `json:"callbacks,omitempty"` // outbound webhooks added or changed by the PR
 	Summary        string        `json:"summary"`
 	Confidence     float64       `json:"confidence"`
 	PostmanUpdate  PostmanUpdate `json:"postman_update"`
+	Repository     string        `json:"repository,omitempty"`
+	PRNumber       int           `json:"pr_number,omitempty"`
+	HeadSHA        string        `json:"head_sha,omitempty"`
+	Truncated      bool          `json:"truncated,omitempty"`
+	FlaggedRoutes  []APIRoute    `json:"flagged_routes,omitempty"` // low-confidence routes held back for review
+	PromptDebug    *PromptDebug  `json:"prompt_debug,omitempty"`   // admin-only, see X-Debug-Prompt
+	Usage          *TokenUsage   `json:"usage,omitempty"`          // Claude tokens spent, summed over every request of the analysis
+
+	// BreakingChanges lists changes that can break existing clients, reported by Claude or computed
+	BreakingChanges []string `json:"breaking_changes,omitempty"`
+	// AnalysisPath is direct when Claude analyzed the diff itself, summarized when the prompt was too
+	// large and a summary of the diff was analyzed instead
+	AnalysisPath string `json:"analysis_path,omitempty"`
+	// SchemaVersion is the AnalysisSchemaVersion the result was produced with, see DecodeAnalysis
+	SchemaVersion int `json:"schema_version,omitempty"`
+	// Processed is false when the PR was not analyzed (skipped action, draft, debounced...), so
+	// callers can tell a skip from an analysis that found no changes; SkipReason says why
+	Processed  bool   `json:"processed"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	// Channels are message channels (topics, queues, subjects) the PR added, changed or removed
+	Channels []Channel `json:"channels,omitempty"`
+}
+
+// Analysis paths
+const (
+	AnalysisPathDirect     = "direct"
+	AnalysisPathSummarized = "summarized"
+)
+
+// TokenUsage is the Claude token usage of an analysis
+type TokenUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// RouteCount returns the total number of new, modified and deleted routes
+func (r *AnalysisResponse) RouteCount() int {
+	return len(r.NewRoutes) + len(r.ModifiedRoutes) + len(r.DeletedRoutes)
+}
+
+// AggregateConfidence averages per-route confidence scores. Routes without a score count
+// at the overall confidence; with no routes the overall confidence is returned unchanged.
+func (r *AnalysisResponse) AggregateConfidence() float64 {
+	total, count := 0.0, 0
+	for _, routes := range [][]APIRoute{r.NewRoutes, r.ModifiedRoutes, r.DeletedRoutes} {
+		for _, route := range routes {
+			total += route.EffectiveConfidence(r.Confidence)
+			count++
+		}
+	}
+	if count == 0 {
+		return r.Confidence
+	}
+	return total / float64(count)
 }
 
 // APIRoute represents an API route with its details
@@ -34,21 +110,120 @@ type APIRoute struct {
 	Description string         `json:"description"`
 	Parameters  []Parameter    `json:"parameters,omitempty"`
 	RequestBody map[string]any `json:"request_body,omitempty"`
-	Response    map[string]any `json:"response,omitempty"`
+	Response    map[string]any `json:"response,omitempty"` // success body, used when Responses is empty
 	Headers     []Header       `json:"headers,omitempty"`
 	Tags        []string       `json:"tags,omitempty"`
-	Deprecated  bool           `json:"deprecated,omitempty"`
+	Deprecated  bool           `json:"deprecated,omitempty"`       // still served but discouraged
+	Removed     bool           `json:"removed,omitempty"`          // deleted from the codebase entirely
+	Version     string         `json:"version,omitempty"`          // e.g. v2, detected from the path
+	PrevVersion string         `json:"previous_version,omitempty"` // version this route supersedes
+	Confidence  float64        `json:"confidence,omitempty"`       // 0-1, how sure Claude is about this route
+	Pagination  string         `json:"pagination,omitempty"`       // page, offset or cursor for list endpoints
+	SourceFile  string         `json:"source_file,omitempty"`      // file in the diff where the route is defined
+	SourceLine  int            `json:"source_line,omitempty"`      // line in the new version of SourceFile
+
+	// Responses maps HTTP status codes ("201", "404", ...) to documented responses, including errors
+	Responses map[string]RouteResponse `json:"responses,omitempty"`
+	// Auth is the authentication the route requires, when the analysis could tell
+	Auth *RouteAuth `json:"auth,omitempty"`
+	// RateLimit is the rate limiting applied to the route and the headers it answers with
+	RateLimit *RouteRateLimit `json:"rate_limit,omitempty"`
+}
+
+// RouteRateLimit describes a route's rate limiting
+type RouteRateLimit struct {
+	Limit   int      `json:"limit,omitempty"`   // requests allowed per window
+	Window  string   `json:"window,omitempty"`  // e.g. 1s, 1m, 1h
+	Scope   string   `json:"scope,omitempty"`   // what the limit is counted per, e.g. ip, user, api_key
+	Headers []string `json:"headers,omitempty"` // rate-limit response headers, e.g. X-RateLimit-Remaining
+}
+
+// Authentication schemes a route can require
+const (
+	AuthSchemeNone   = "none"
+	AuthSchemeBearer = "bearer"
+	AuthSchemeAPIKey = "api_key"
+	AuthSchemeBasic  = "basic"
+	AuthSchemeOAuth2 = "oauth2"
+)
+
+// RouteAuth describes a route's authentication and whether the PR changed it
+type RouteAuth struct {
+	Scheme     string `json:"scheme"`                // one of the AuthScheme constants
+	HeaderName string `json:"header_name,omitempty"` // for api_key, e.g. X-API-Key
+	Changed    bool   `json:"changed,omitempty"`     // auth or security middleware changed in this PR
+	Note       string `json:"note,omitempty"`        // what changed, e.g. "now requires admin role"
+}
+
+// Pagination styles detected on list endpoints
+const (
+	PaginationPage   = "page"   // page + limit
+	PaginationOffset = "offset" // offset + limit
+	PaginationCursor = "cursor" // cursor + limit
+)
+
+// EffectiveConfidence returns the route's own confidence, falling back to the overall score
+func (r APIRoute) EffectiveConfidence(overall float64) float64 {
+	if r.Confidence > 0 {
+		return r.Confidence
+	}
+	return overall
+}
+
+// RouteResponse documents the response returned for one status code
+type RouteResponse struct {
+	Description string         `json:"description,omitempty"`
+	Body        map[string]any `json:"body,omitempty"`
 }
 
 // Parameter represents an API parameter
 type Parameter struct {
-	Name        string `json:"name"`
-	In          string `json:"in"` // query, path, header, body
-	Type        string `json:"type"`
-	Required    bool   `json:"required"`
-	Description string `json:"description"`
-	Default     any    `json:"default,omitempty"`
-	Example     any    `json:"example,omitempty"`
+	Name        string   `json:"name"`
+	In          string   `json:"in"` // query, path, header, body
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+	Default     any      `json:"default,omitempty"`
+	Example     any      `json:"example,omitempty"`
+	Enum        []any    `json:"enum,omitempty"`
+	Format      string   `json:"format,omitempty"` // date-time, uuid, email, etc.
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+}
+
+// Constraints returns a human readable summary of the parameter's enum, format and range constraints
+func (p Parameter) Constraints() string {
+	var parts []string
+	if p.Format != "" {
+		parts = append(parts, "format: "+p.Format)
+	}
+	if len(p.Enum) > 0 {
+		values := make([]string, len(p.Enum))
+		for i, v := range p.Enum {
+			values[i] = fmt.Sprintf("%v", v)
+		}
+		parts = append(parts, "one of: "+strings.Join(values, ", "))
+	}
+	if p.Minimum != nil {
+		parts = append(parts, "min: "+strconv.FormatFloat(*p.Minimum, 'f', -1, 64))
+	}
+	if p.Maximum != nil {
+		parts = append(parts, "max: "+strconv.FormatFloat(*p.Maximum, 'f', -1, 64))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DescriptionWithConstraints appends the constraint summary to the parameter description
+func (p Parameter) DescriptionWithConstraints() string {
+	constraints := p.Constraints()
+	switch {
+	case constraints == "":
+		return p.Description
+	case p.Description == "":
+		return "(" + constraints + ")"
+	default:
+		return p.Description + " (" + constraints + ")"
+	}
 }
 
 // Header represents an HTTP header
@@ -59,13 +234,88 @@ type Header struct {
 	Example     any    `json:"example,omitempty"`
 }
 
+// Callback documents an outbound webhook the API sends to subscribers, like OpenAPI's callbacks
+type Callback struct {
+	Name        string         `json:"name"`          // event name, e.g. order.created
+	Method      string         `json:"method"`        // usually POST
+	URL         string         `json:"url,omitempty"` // where it is sent, e.g. {$request.body#/callbackUrl}
+	Description string         `json:"description"`
+	Payload     map[string]any `json:"payload,omitempty"`      // example body sent to the subscriber
+	Headers     []Header       `json:"headers,omitempty"`      // e.g. signature headers
+	TriggeredBy string         `json:"triggered_by,omitempty"` // route registering the subscriber, e.g. "POST /subscriptions"
+}
+
+// Channel is a message channel of an event-driven API
+type Channel struct {
+	Name        string         `json:"name"`               // address, e.g. orders.created or orders-topic
+	Action      string         `json:"action"`             // ChannelActionPublish or ChannelActionConsume, from the service's side
+	Change      string         `json:"change"`             // ChannelChangeNew, ChannelChangeModified or ChannelChangeDeleted
+	Protocol    string         `json:"protocol,omitempty"` // kafka, amqp, nats, sqs, sns, mqtt, ws...
+	Description string         `json:"description,omitempty"`
+	Message     ChannelMessage `json:"message"`
+}
+
+// ChannelMessage is the message carried on a channel
+type ChannelMessage struct {
+	Name        string         `json:"name,omitempty"`
+	ContentType string         `json:"content_type,omitempty"`
+	Payload     map[string]any `json:"payload,omitempty"` // example payload
+	Headers     []Header       `json:"headers,omitempty"`
+}
+
+// Channel actions, from the analyzed service's point of view
+const (
+	ChannelActionPublish = "publish" // the service sends messages to the channel
+	ChannelActionConsume = "consume" // the service receives messages from the channel
+)
+
+// Channel changes
+const (
+	ChannelChangeNew      = "new"
+	ChannelChangeModified = "modified"
+	ChannelChangeDeleted  = "deleted"
+)
+
+// Statuses of analyses skipped because of the diff
+const (
+	PostmanStatusEmptyDiff    = "empty_diff"     // the PR diff had no changes
+	PostmanStatusDiffTooSmall = "diff_too_small" // the diff was below MIN_DIFF_BYTES
+	PostmanStatusBusy         = "busy"           // another write held the collection past POSTMAN_WRITE_LOCK_TIMEOUT
+)
+
 // PostmanUpdate represents the result of updating Postman
 type PostmanUpdate struct {
 	CollectionID  string `json:"collection_id"`
-	Status        string `json:"status"` // success, error, partial
+	Status        string `json:"status"`                // success, error, partial, skipped, empty_diff, diff_too_small, dry_run, busy
+	UpdateMode    string `json:"update_mode,omitempty"` // full, additive or annotate
+	JobID         string `json:"job_id,omitempty"`      // background update to poll while Status is postman_pending
 	ItemsAdded    int    `json:"items_added"`
 	ItemsModified int    `json:"items_modified"`
 	ItemsDeleted  int    `json:"items_deleted"`
+	ItemsFailed   int    `json:"items_failed,omitempty"` // routes skipped because they couldn't be documented
 	ErrorMessage  string `json:"error_message,omitempty"`
+	ErrorType     string `json:"error_type,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"` // stable code, see pkg/errors/codes.go
 	UpdatedAt     string `json:"updated_at"`
+
+	// Preview is a unified diff of the collection changes, set on dry runs
+	Preview string `json:"preview,omitempty"`
+	// VariablesAdded lists collection variables created, empty, for {{name}} references without a definition
+	VariablesAdded []string `json:"variables_added,omitempty"`
+}
+
+// Failed reports whether the Postman update was attempted and failed
+func (u PostmanUpdate) Failed() bool {
+	return u.Status == "error"
+}
+
+// Settled reports whether the update reached a final state that repeating the request would only
+// reproduce. Failed, partial and busy updates and deferred ones (queued, debounced, cached or
+// pending in the background) are not settled.
+func (u PostmanUpdate) Settled() bool {
+	switch u.Status {
+	case "error", "partial", "queued", "debounced", "cached", PostmanStatusBusy, PostmanStatusPending:
+		return false
+	}
+	return true
 }
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AnalysisSchemaVersion is the current version of the serialized AnalysisResponse. Bump it and
+// add a migration to analysisMigrations whenever a change needs old records to be rewritten.
+const AnalysisSchemaVersion = 2
+
+// analysisMigrations upgrade a decoded record from the version it is keyed by to the next one
+var analysisMigrations = map[int]func(record map[string]any){
+	// Records from before versioning predate diff summarization, so Claude always saw the diff
+	0: func(record map[string]any) {
+		if _, ok := record["analysis_path"]; !ok {
+			record["analysis_path"] = AnalysisPathDirect
+		}
+	},
+	// Version 2 added processed; skipped PRs were never stored, so older records were analyzed
+	1: func(record map[string]any) {
+		if _, ok := record["processed"]; !ok {
+			record["processed"] = true
+		}
+	},
+}
+
+// DecodeAnalysis reads a stored analysis record of any known schema version, upgrading it to
+// AnalysisSchemaVersion. Records without schema_version are version 0.
+func DecodeAnalysis(data []byte) (*AnalysisResponse, error) {
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode analysis record: %w", err)
+	}
+
+	version := 0
+	if raw, ok := record["schema_version"]; ok {
+		v, ok := raw.(float64)
+		if !ok || v != float64(int(v)) || v < 0 {
+			return nil, fmt.Errorf("invalid analysis schema_version %v", raw)
+		}
+		version = int(v)
+	}
+	if version > AnalysisSchemaVersion {
+		return nil, fmt.Errorf("analysis schema_version %d is newer than supported version %d", version, AnalysisSchemaVersion)
+	}
+
+	if version < AnalysisSchemaVersion {
+		for ; version < AnalysisSchemaVersion; version++ {
+			if migrate, ok := analysisMigrations[version]; ok {
+				migrate(record)
+			}
+		}
+		record["schema_version"] = AnalysisSchemaVersion
+
+		var err error
+		if data, err = json.Marshal(record); err != nil {
+			return nil, fmt.Errorf("failed to encode migrated analysis record: %w", err)
+		}
+	}
+
+	var resp AnalysisResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode analysis record: %w", err)
+	}
+	return &resp, nil
+}
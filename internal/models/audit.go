@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Audit actions
+const (
+	AuditActionCredentialUse = "credential.use"
+	AuditActionAnalysis      = "analysis.complete"
+	AuditActionPostmanWrite  = "postman.write"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeSkipped = "skipped"
+)
+
+// AuditEvent is one entry of the audit log: who did what to which resource, when, and how it ended
+type AuditEvent struct {
+	Time     time.Time      `json:"time"`
+	Action   string         `json:"action"`
+	Actor    string         `json:"actor"`             // GitHub sender, entry point or "pr-documentator"
+	Resource string         `json:"resource"`          // e.g. "claude:key-1", "postman:collection/<id>", "github:owner/repo#42"
+	Outcome  string         `json:"outcome"`           // success, failure or skipped
+	Details  map[string]any `json:"details,omitempty"` // string values are scanned for secrets before writing
+}
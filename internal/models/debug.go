@@ -0,0 +1,34 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// PromptDebug captures the exact requests sent to Claude during an analysis, for admin debugging
+type PromptDebug struct {
+	mu       sync.Mutex
+	Requests []json.RawMessage `json:"requests"`
+}
+
+type promptDebugKey struct{}
+
+// WithPromptDebug returns a context whose Claude requests are recorded in the returned PromptDebug
+func WithPromptDebug(ctx context.Context) (context.Context, *PromptDebug) {
+	debug := &PromptDebug{}
+	return context.WithValue(ctx, promptDebugKey{}, debug), debug
+}
+
+// PromptDebugFrom returns the PromptDebug attached to ctx, or nil when capture is off
+func PromptDebugFrom(ctx context.Context) *PromptDebug {
+	debug, _ := ctx.Value(promptDebugKey{}).(*PromptDebug)
+	return debug
+}
+
+// Record appends a marshaled Claude request
+func (d *PromptDebug) Record(request []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Requests = append(d.Requests, json.RawMessage(append([]byte(nil), request...)))
+}
@@ -0,0 +1,19 @@
+package models
+
+import "context"
+
+// PostmanStatusDryRun marks a Postman update that was computed but not written
+const PostmanStatusDryRun = "dry_run"
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context whose Postman updates are previewed instead of written
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether Postman updates under ctx must not be written
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Lifecycle event types published to the analytics event sink
+const (
+	EventAnalysisStarted   = "analysis.started"
+	EventAnalysisCompleted = "analysis.completed"
+	EventRoutesDiscovered  = "routes.discovered"
+	EventPostmanUpdated    = "postman.updated"
+)
+
+// Event is one structured analysis lifecycle event. Data holds the type-specific fields; its
+// keys are stable so the warehouse can rely on them.
+type Event struct {
+	Time       time.Time      `json:"time"`
+	Type       string         `json:"type"`
+	Repository string         `json:"repository"`
+	PRNumber   int            `json:"pr_number"`
+	Source     string         `json:"source"` // webhook, manual, queue...
+	Data       map[string]any `json:"data,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "context"
+
+type forceKey struct{}
+
+// WithForce returns a context for a deliberate re-run that must bypass cached analyses
+func WithForce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceKey{}, true)
+}
+
+// IsForced reports whether ctx belongs to a deliberate re-run
+func IsForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceKey{}).(bool)
+	return forced
+}
@@ -4,25 +4,36 @@ import "time"
 
 // GitHubPRPayload represents the GitHub PR webhook payload
 type GitHubPRPayload struct {
-	Action      string      `json:"action"`
-	Number      int         `json:"number"`
-	PullRequest PullRequest `json:"pull_request"`
-	Repository  Repository  `json:"repository"`
-	Sender      User        `json:"sender"`
-	Diff        string      `json:"diff,omitempty"` // For manual analysis
+	Action       string      `json:"action" validate:"required"`
+	Number       int         `json:"number"`
+	PullRequest  PullRequest `json:"pull_request" validate:"required"`
+	Repository   Repository  `json:"repository" validate:"required"`
+	Sender       User        `json:"sender"`
+	Diff         string      `json:"diff,omitempty"`          // For manual analysis
+	CollectionID string      `json:"collection_id,omitempty"` // Optional target collection override
+	Source       string      `json:"-"`                       // Entry point that submitted the PR, for metrics
 }
 
+// Entry points submitting PRs for analysis
+const (
+	PayloadSourceWebhook = "webhook"
+	PayloadSourceManual  = "manual"
+	PayloadSourceQueue   = "queue"
+)
+
 // PullRequest represents a GitHub pull request
 type PullRequest struct {
 	ID        int        `json:"id"`
-	Number    int        `json:"number"`
+	Number    int        `json:"number" validate:"required"`
 	Title     string     `json:"title"`
 	Body      string     `json:"body"`
 	State     string     `json:"state"`
+	Draft     bool       `json:"draft"`
 	User      User       `json:"user"`
 	Head      Branch     `json:"head"`
 	Base      Branch     `json:"base"`
-	DiffURL   string     `json:"diff_url"`
+	URL       string     `json:"url"` // REST API URL, used for authenticated diff fetches
+	DiffURL   string     `json:"diff_url" validate:"required,url"`
 	PatchURL  string     `json:"patch_url"`
 	HTMLURL   string     `json:"html_url"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -34,7 +45,7 @@ type PullRequest struct {
 type Repository struct {
 	ID       int    `json:"id"`
 	Name     string `json:"name"`
-	FullName string `json:"full_name"`
+	FullName string `json:"full_name" validate:"required"`
 	Owner    User   `json:"owner"`
 	HTMLURL  string `json:"html_url"`
 	CloneURL string `json:"clone_url"`
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PostmanStatusPending marks a Postman update still running in the background
+const PostmanStatusPending = "postman_pending"
+
+// PostmanJob tracks a background Postman update
+type PostmanJob struct {
+	ID          string        `json:"id"`
+	Repository  string        `json:"repository,omitempty"`
+	PRNumber    int           `json:"pr_number,omitempty"`
+	Status      string        `json:"status"` // postman_pending until the update finishes, then its result status
+	Update      PostmanUpdate `json:"postman_update"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+}
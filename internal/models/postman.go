@@ -1,6 +1,18 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
+
+// collectionIDPattern matches Postman collection IDs and UIDs (owner-prefixed IDs)
+var collectionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,127}$`)
+
+// ValidCollectionID reports whether id looks like a Postman collection ID or UID,
+// so it can be safely placed in a Postman API path
+func ValidCollectionID(id string) bool {
+	return collectionIDPattern.MatchString(id)
+}
 
 // PostmanCollection represents a Postman collection
 type PostmanCollection struct {
@@ -20,6 +32,7 @@ type PostmanInfo struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Schema      string `json:"schema"`
+	UpdatedAt   string `json:"updatedAt,omitempty"` // set by Postman, used to detect concurrent edits
 }
 
 // PostmanItem represents a request item in Postman
@@ -87,8 +100,18 @@ type PostmanVariable struct {
 
 // PostmanAuth represents authentication
 type PostmanAuth struct {
-	Type   string         `json:"type"`
-	Config map[string]any `json:"config,omitempty"`
+	Type   string                 `json:"type"` // noauth, bearer, apikey, basic, oauth2
+	Config map[string]any         `json:"config,omitempty"`
+	Bearer []PostmanAuthAttribute `json:"bearer,omitempty"`
+	APIKey []PostmanAuthAttribute `json:"apikey,omitempty"`
+	Basic  []PostmanAuthAttribute `json:"basic,omitempty"`
+}
+
+// PostmanAuthAttribute is one setting of an auth type, e.g. the bearer token
+type PostmanAuthAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
 }
 
 // PostmanResponse represents a response example
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Collection sync health, derived from consecutive failed updates
+const (
+	SyncHealthHealthy  = "healthy"  // last update succeeded
+	SyncHealthDegraded = "degraded" // recent updates failed
+	SyncHealthFailing  = "failing"  // SyncFailingThreshold or more updates failed in a row
+)
+
+// SyncFailingThreshold is the number of consecutive failed updates that marks a collection failing
+const SyncFailingThreshold = 3
+
+// SyncStatus tracks how current a Postman collection's documentation is
+type SyncStatus struct {
+	CollectionID        string     `json:"collection_id"`
+	Health              string     `json:"health"`
+	LastSyncAt          *time.Time `json:"last_sync_at,omitempty"` // last successful update
+	LastAttemptAt       time.Time  `json:"last_attempt_at"`
+	LastRepository      string     `json:"last_repository,omitempty"`
+	LastPRNumber        int        `json:"last_pr_number,omitempty"` // last PR synced successfully
+	TotalItems          int        `json:"total_items"`              // requests in the collection after the last sync
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// analysisCache keeps Claude analyses keyed by the analysis request, model and target collection,
+// so the same request is analyzed once no matter which entry point submitted it. Entries are stored
+// serialized and decoded per hit, as every caller mutates its analysis afterwards.
+type analysisCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+}
+
+type analysisCacheEntry struct {
+	key          string
+	collectionID string
+	analysis     []byte
+	expiresAt    time.Time
+}
+
+func newAnalysisCache(ttl time.Duration, maxEntries int) *analysisCache {
+	return &analysisCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// analysisCacheKey hashes everything that determines Claude's answer: the diff along with the
+// collection context, PR details and intended changes the prompt is built from. PR fields the
+// prompt never shows, like timestamps and the head SHA, are left out.
+func analysisCacheKey(req models.AnalysisRequest, model, collectionID string) string {
+	req.PullRequest = models.PullRequest{
+		Number:  req.PullRequest.Number,
+		Title:   req.PullRequest.Title,
+		Body:    req.PullRequest.Body,
+		DiffURL: req.PullRequest.DiffURL,
+	}
+	req.Repository = models.Repository{FullName: req.Repository.FullName}
+
+	h := sha256.New()
+	for _, part := range []string{model, collectionID} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	// Marshaling a request of plain values can't fail
+	_ = json.NewEncoder(h).Encode(req)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *analysisCache) get(key string) (*models.AnalysisResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*analysisCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+
+	var analysis models.AnalysisResponse
+	if err := json.Unmarshal(entry.analysis, &analysis); err != nil {
+		return nil, false
+	}
+	return &analysis, true
+}
+
+func (c *analysisCache) put(key, collectionID string, analysis *models.AnalysisResponse) {
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &analysisCacheEntry{key: key, collectionID: collectionID, analysis: data, expiresAt: time.Now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*analysisCacheEntry).key)
+	}
+}
+
+// invalidateCollection drops every entry analyzed against one of collectionIDs and returns how many
+func (c *analysisCache) invalidateCollection(collectionIDs ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*analysisCacheEntry)
+		for _, id := range collectionIDs {
+			if entry.collectionID == id {
+				c.order.Remove(elem)
+				delete(c.entries, entry.key)
+				removed++
+				break
+			}
+		}
+		elem = next
+	}
+	return removed
+}
+
+// CollectionChanged drops cached analyses made against collectionID, whose existing routes are now
+// stale. Analyses of the default collection are stored without an ID, so they're dropped when the
+// default collection changes.
+func (s *AnalyzerService) CollectionChanged(collectionID string) int {
+	if s.analysisCache == nil || collectionID == "" {
+		return 0
+	}
+	ids := []string{collectionID}
+	if collectionID == s.config.DefaultCollectionID {
+		ids = append(ids, "")
+	}
+	removed := s.analysisCache.invalidateCollection(ids...)
+	s.logger.Info("Invalidated cached analyses for changed collection", "collection_id", collectionID, "entries", removed)
+	return removed
+}
+
+// cachedAnalysis runs the Claude analysis unless an identical one is cached. Prompt debugging
+// always reaches Claude, since a replayed analysis has no prompt to show, and forced re-runs
+// skip the lookup but refresh the cached entry.
+func (s *AnalyzerService) cachedAnalysis(ctx context.Context, payload models.GitHubPRPayload, req models.AnalysisRequest) (*models.AnalysisResponse, error) {
+	if s.analysisCache == nil || models.PromptDebugFrom(ctx) != nil {
+		return s.claudeClient.AnalyzePR(ctx, req)
+	}
+
+	source := payload.Source
+	if source == "" {
+		source = models.PayloadSourceWebhook
+	}
+
+	key := analysisCacheKey(req, s.config.ClaudeModelFor(req.Repository.FullName), payload.CollectionID)
+	result := "miss"
+	if models.IsForced(ctx) {
+		result = "forced"
+	} else if cached, ok := s.analysisCache.get(key); ok {
+		s.logger.Info("Reusing cached analysis of identical request", "entry_point", source, "pr_number", payload.PullRequest.Number)
+		s.metrics.IncrementCounter("analysis_cache_requests_total", map[string]string{"entry_point": source, "result": "hit"})
+		// The tokens were spent by the analysis that filled the cache, not this one
+		cached.Usage = &models.TokenUsage{}
+		return cached, nil
+	}
+	s.metrics.IncrementCounter("analysis_cache_requests_total", map[string]string{"entry_point": source, "result": result})
+
+	analysis, err := s.claudeClient.AnalyzePR(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.analysisCache.put(key, payload.CollectionID, analysis)
+	return analysis, nil
+}
@@ -5,50 +5,233 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/igorsal/pr-documentator/internal/config"
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/diffparse"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/limiter"
 )
 
 type AnalyzerService struct {
 	claudeClient  interfaces.ClaudeClient
 	postmanClient interfaces.PostmanClient
+	config        config.AnalyzerConfig
+	transformers  *TransformerChain
+	notifiers     []interfaces.ResultNotifier
 	logger        interfaces.Logger
 	metrics       interfaces.MetricsCollector
+	inFlight      atomic.Int64
+	debouncer     *prDebouncer
+	fallback      *breakerFallback
+	postmanJobs   *postmanJobStore
+	background    sync.WaitGroup
+	analysisCache *analysisCache
+	batch         *limiter.Adaptive
+	audit         interfaces.AuditLogger
+	events        interfaces.EventEmitter
+	githubToken   atomic.Value // string; replaced by SetGitHubToken when the secret manager rotates it
+
+	diffTransformers *DiffTransformerChain
 }
 
 // NewAnalyzerService creates a new analyzer service
-func NewAnalyzerService(claudeClient interfaces.ClaudeClient, postmanClient interfaces.PostmanClient, logger interfaces.Logger, metrics interfaces.MetricsCollector) *AnalyzerService {
-	return &AnalyzerService{
+func NewAnalyzerService(claudeClient interfaces.ClaudeClient, postmanClient interfaces.PostmanClient, cfg config.AnalyzerConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *AnalyzerService {
+	s := &AnalyzerService{
 		claudeClient:  claudeClient,
 		postmanClient: postmanClient,
+		config:        cfg,
+		transformers:  NewTransformerChain(),
 		logger:        logger,
 		metrics:       metrics,
+		fallback:      newBreakerFallback(cfg.BreakerFallback),
+		postmanJobs:   newPostmanJobStore(cfg.JobRetention, cfg.JobMaxRecords, metrics),
+		batch:         newBatchLimiter(cfg.BatchConcurrency, metrics),
+		events:        nopEventEmitter{},
 	}
+	if cfg.PRCooldown > 0 {
+		s.debouncer = newPRDebouncer(cfg.PRCooldown, &s.background)
+	}
+	if cfg.AnalysisCacheTTL > 0 {
+		s.analysisCache = newAnalysisCache(cfg.AnalysisCacheTTL, cfg.AnalysisCacheSize)
+	}
+	s.githubToken.Store(cfg.GitHubToken)
+	if cfg.JobCleanupInterval > 0 {
+		go s.postmanJobs.cleanup(cfg.JobCleanupInterval)
+	}
+	s.diffTransformers = NewDiffTransformerChain(logger)
+	return s
+}
+
+// RegisterTransformer adds a transformer applied between Claude analysis and the Postman update
+func (s *AnalyzerService) RegisterTransformer(t interfaces.ResponseTransformer) {
+	s.transformers.Register(t)
+}
+
+// RegisterDiffTransformer adds a transformer applied to the diff right before it is sent to Claude
+func (s *AnalyzerService) RegisterDiffTransformer(t interfaces.DiffTransformer) {
+	s.diffTransformers.Register(t)
+}
+
+// RegisterNotifier adds a notifier that receives every completed analysis
+func (s *AnalyzerService) RegisterNotifier(n interfaces.ResultNotifier) {
+	s.notifiers = append(s.notifiers, n)
+}
+
+// InFlight returns the number of analyses currently running
+func (s *AnalyzerService) InFlight() int64 {
+	return s.inFlight.Load()
 }
 
 // AnalyzePR analyzes a pull request and updates Postman documentation
-func (s *AnalyzerService) AnalyzePR(ctx context.Context, payload models.GitHubPRPayload) (*models.AnalysisResponse, error) {
+func (s *AnalyzerService) AnalyzePR(ctx context.Context, payload models.GitHubPRPayload) (result *models.AnalysisResponse, err error) {
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+
+	decision := newAnalysisDecision(payload)
+	defer func() { s.logDecision(decision, result, err) }()
+	defer func() {
+		if result != nil {
+			result.SchemaVersion = models.AnalysisSchemaVersion
+			result.Processed = decision.skipReason == ""
+			result.SkipReason = decision.skipReason
+		}
+	}()
+
 	s.logger.Info("Starting PR analysis",
 		"pr_number", payload.PullRequest.Number,
 		"repo", payload.Repository.FullName,
 		"action", payload.Action,
 	)
+	s.emitEvent(models.EventAnalysisStarted, payload, map[string]any{"action": payload.Action, "head_sha": payload.PullRequest.Head.SHA})
 
 	// Only process opened, synchronize, or reopened PRs
 	if !s.shouldProcessAction(payload.Action) {
 		s.logger.Info("Skipping PR action", "action", payload.Action)
+		decision.skip("action")
 		return &models.AnalysisResponse{
 			Summary: fmt.Sprintf("Skipped action: %s", payload.Action),
 		}, nil
 	}
 
-	// Fetch the PR diff
-	diff, err := s.fetchPRDiff(ctx, payload.PullRequest.DiffURL)
-	if err != nil {
-		s.logger.Error("Failed to fetch PR diff", err, "diff_url", payload.PullRequest.DiffURL)
-		return nil, fmt.Errorf("failed to fetch PR diff: %w", err)
+	// Draft PRs are usually not ready to be documented
+	if s.config.SkipDraftPRs && payload.PullRequest.Draft {
+		s.logger.Info("Skipping draft PR", "pr_number", payload.PullRequest.Number)
+		decision.skip("draft")
+		return &models.AnalysisResponse{
+			Summary: "Skipped draft PR",
+			PostmanUpdate: models.PostmanUpdate{
+				Status:    "skipped_draft",
+				UpdatedAt: time.Now().Format(time.RFC3339),
+			},
+		}, nil
+	}
+
+	// Coalesce rapid successive pushes to the same PR
+	if resp, deferred := s.debounceAnalysis(ctx, payload); deferred {
+		decision.skip("debounced")
+		return resp, nil
+	}
+
+	// Optionally triage the PR description first, which is much cheaper than a full diff analysis
+	var intendedChanges []string
+	if s.config.UseDescriptionFirst && (payload.PullRequest.Title != "" || payload.PullRequest.Body != "") {
+		triage, err := s.claudeClient.TriageDescription(ctx, models.AnalysisRequest{
+			PullRequest: payload.PullRequest,
+			Repository:  payload.Repository,
+		})
+		if err != nil {
+			s.logger.Warn("Failed to triage PR description, falling back to full analysis", "error", err)
+		} else if !triage.HasAPIChanges {
+			s.logger.Info("PR description indicates no API changes, skipping diff analysis",
+				"pr_number", payload.PullRequest.Number,
+			)
+			decision.skip("description")
+			return &models.AnalysisResponse{
+				Summary:    triage.Summary,
+				Repository: payload.Repository.FullName,
+				PRNumber:   payload.PullRequest.Number,
+				PostmanUpdate: models.PostmanUpdate{
+					Status:    "skipped",
+					UpdatedAt: time.Now().Format(time.RFC3339),
+				},
+			}, nil
+		} else {
+			intendedChanges = triage.IntendedChanges
+		}
+	}
+
+	// Use the inline diff when provided (manual analysis), otherwise fetch it from GitHub
+	diff := payload.Diff
+	if diff == "" {
+		fetched, err := s.fetchPRDiff(ctx, payload)
+		if err != nil {
+			s.logger.Error("Failed to fetch PR diff", err, "diff_url", payload.PullRequest.DiffURL)
+			return nil, fmt.Errorf("failed to fetch PR diff: %w", err)
+		}
+		diff = fetched
+		// Queued retries of this analysis reuse the exact diff instead of refetching it from
+		// GitHub, where the PR may have changed or become unavailable in the meantime
+		payload.Diff = fetched
+	}
+	decision.diffSizeBytes = len(diff)
+
+	// Nothing to analyze, e.g. a PR with only merge commits
+	if strings.TrimSpace(diff) == "" {
+		s.logger.Info("Skipping PR with empty diff", "pr_number", payload.PullRequest.Number)
+		decision.skip("empty_diff")
+		return skippedDiffResponse(payload, models.PostmanStatusEmptyDiff, "No changes in the diff."), nil
+	}
+
+	// Trivial diffs rarely change the API and aren't worth a Claude call
+	if s.config.MinDiffBytes > 0 && len(diff) < s.config.MinDiffBytes {
+		s.logger.Info("Skipping PR with diff below minimum size",
+			"pr_number", payload.PullRequest.Number,
+			"diff_size_bytes", len(diff),
+			"min_diff_bytes", s.config.MinDiffBytes,
+		)
+		decision.skip("diff_too_small")
+		return skippedDiffResponse(payload, models.PostmanStatusDiffTooSmall,
+			fmt.Sprintf("Diff is smaller than %d bytes, skipped analysis.", s.config.MinDiffBytes)), nil
+	}
+
+	if s.config.MaxDiffBytes > 0 && len(diff) > s.config.MaxDiffBytes {
+		s.logger.Warn("PR diff exceeds size limit", "diff_size_bytes", len(diff), "max_diff_bytes", s.config.MaxDiffBytes)
+		return nil, pkgerrors.NewValidationError(fmt.Sprintf("diff exceeds maximum size of %d bytes", s.config.MaxDiffBytes)).
+			WithCode(pkgerrors.CodeDiffTooLarge).
+			WithContext("diff_size_bytes", len(diff))
+	}
+
+	// More context around changes helps Claude on ambiguous diffs, at the cost of tokens
+	diff = s.expandDiffContext(ctx, payload, diff)
+
+	// Keep committed secrets out of the third-party LLM
+	if s.config.RedactSecrets {
+		redacted, err := s.redactSecrets(payload, diff)
+		if err != nil {
+			return nil, err
+		}
+		diff = redacted
+	}
+
+	// Configured preprocessing (comment stripping, path filters...), after redaction so it never
+	// sees secrets
+	if s.diffTransformers.Len() > 0 {
+		transformed, err := s.diffTransformers.Transform(ctx, diff)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(transformed) == "" {
+			s.logger.Info("Skipping PR with nothing left to analyze after diff transformations", "pr_number", payload.PullRequest.Number)
+			decision.skip("empty_diff")
+			return skippedDiffResponse(payload, models.PostmanStatusEmptyDiff, "No changes left after diff transformations."), nil
+		}
+		diff = transformed
 	}
 
 	// 	diff := `diff --git a/.gitignore b/.gitignore
@@ -198,30 +381,86 @@ func (s *AnalyzerService) AnalyzePR(ctx context.Context, payload models.GitHubPR
 
 	// Create analysis request
 	analysisReq := models.AnalysisRequest{
-		PullRequest: payload.PullRequest,
-		Repository:  payload.Repository,
-		Diff:        diff,
+		PullRequest:     payload.PullRequest,
+		Repository:      payload.Repository,
+		Diff:            diff,
+		IntendedChanges: intendedChanges,
+		// Moved files let Claude avoid reporting the same route as deleted and new
+		RenamedFiles: diffparse.Renames(diff),
+		// Routes declared in specs and gateway configs are analyzed differently from code
+		DeclarativeFiles: diffparse.DeclarativeFiles(diff),
 	}
 
 	// Get existing collection context for better analysis
-	existingCollection, err := s.postmanClient.GetCollection(ctx)
+	existingRoutes, err := s.loadExistingRoutes(ctx, payload)
 	if err != nil {
-		s.logger.Warn("Failed to get existing collection context", "error", err)
-		// Continue without context - don't fail the entire operation
+		return nil, err
 	}
 
 	// Add collection context to analysis request
-	if existingCollection != nil {
-		analysisReq.ExistingRoutes = s.extractRoutesFromCollection(existingCollection)
+	if len(existingRoutes) > 0 {
+		analysisReq.ExistingRoutes = existingRoutes
 		s.logger.Info("Added collection context", "existing_routes", len(analysisReq.ExistingRoutes))
+		decision.contextRoutes = len(analysisReq.ExistingRoutes)
 	}
 
 	// Analyze with Claude
-	analysisResp, err := s.claudeClient.AnalyzePR(ctx, analysisReq)
+	analysisResp, err := s.cachedAnalysis(ctx, payload, analysisReq)
 	if err != nil {
+		if resp, ok := s.handleBreakerOpen(payload, err); ok {
+			decision.skip("breaker_open")
+			return resp, nil
+		}
 		s.logger.Error("Failed to analyze PR with Claude", err, "pr_number", payload.PullRequest.Number)
 		return nil, fmt.Errorf("claude analysis failed: %w", err)
 	}
+	analysisResp.Repository = payload.Repository.FullName
+	analysisResp.PRNumber = payload.PullRequest.Number
+	analysisResp.HeadSHA = payload.PullRequest.Head.SHA
+
+	// The same route may be reported once from the handler and once from the router
+	s.collapseDuplicateRoutes(analysisResp)
+
+	// Tag routes with their API version and note version bumps
+	s.annotateVersions(analysisResp, analysisReq.ExistingRoutes)
+
+	// Add computed breaking changes to those Claude reported
+	s.flagBreakingChanges(analysisResp, analysisReq.ExistingRoutes)
+
+	// Derive overall confidence from per-route scores and hold back uncertain routes
+	s.applyRouteConfidence(analysisResp)
+
+	// Apply post-analysis transformations
+	if s.transformers.Len() > 0 {
+		analysisResp, err = s.transformers.Transform(ctx, analysisResp)
+		if err != nil {
+			s.logger.Error("Failed to transform analysis response", err, "pr_number", payload.PullRequest.Number)
+			return nil, fmt.Errorf("analysis transformation failed: %w", err)
+		}
+	}
+
+	// Guard against runaway output flooding the collection, counting only the routes that
+	// survived normalization, prefix and filter transformers
+	if err := s.enforceRouteLimit(analysisResp); err != nil {
+		return nil, err
+	}
+
+	// Back Claude's prose with computed counts, paths and a confidence qualifier
+	if s.config.AugmentSummary {
+		analysisResp.Summary = buildAugmentedSummary(analysisResp)
+	}
+	if len(analysisResp.BreakingChanges) > 0 {
+		analysisResp.Summary = breakingChangesSection(analysisResp.BreakingChanges) + "\n" + analysisResp.Summary
+	}
+
+	if len(analysisResp.NewRoutes)+len(analysisResp.ModifiedRoutes)+len(analysisResp.DeletedRoutes) > 0 {
+		s.emitEvent(models.EventRoutesDiscovered, payload, map[string]any{
+			"new_routes":      routeKeys(analysisResp.NewRoutes),
+			"modified_routes": routeKeys(analysisResp.ModifiedRoutes),
+			"deleted_routes":  routeKeys(analysisResp.DeletedRoutes),
+			"confidence":      analysisResp.Confidence,
+		})
+	}
 
 	// Only update Postman if there are changes
 	if s.hasAPIChanges(analysisResp) {
@@ -231,17 +470,11 @@ func (s *AnalyzerService) AnalyzePR(ctx context.Context, payload models.GitHubPR
 			"deleted_routes", len(analysisResp.DeletedRoutes),
 		)
 
-		postmanUpdate, err := s.postmanClient.UpdateCollection(ctx, analysisResp)
-		if err != nil {
-			s.logger.Error("Failed to update Postman collection", err, "pr_number", payload.PullRequest.Number)
-			// Don't fail the entire operation if Postman update fails
-			analysisResp.PostmanUpdate = models.PostmanUpdate{
-				Status:       "error",
-				ErrorMessage: err.Error(),
-				UpdatedAt:    time.Now().Format(time.RFC3339),
-			}
+		// Dry runs stay synchronous so the preview is part of the response
+		if s.config.AsyncPostmanUpdate && !models.IsDryRun(ctx) {
+			analysisResp.PostmanUpdate = s.dispatchPostmanUpdate(payload, analysisResp)
 		} else {
-			analysisResp.PostmanUpdate = *postmanUpdate
+			analysisResp.PostmanUpdate = s.updatePostman(ctx, payload, analysisResp)
 		}
 	} else {
 		s.logger.Info("No API changes detected, skipping Postman update")
@@ -257,6 +490,13 @@ func (s *AnalyzerService) AnalyzePR(ctx context.Context, payload models.GitHubPR
 		"postman_status", analysisResp.PostmanUpdate.Status,
 	)
 
+	s.fallback.remember(payload, analysisResp)
+
+	// Background Postman updates notify once they complete
+	if analysisResp.PostmanUpdate.Status != models.PostmanStatusPending {
+		s.notify(ctx, analysisResp)
+	}
+
 	return analysisResp, nil
 }
 
@@ -270,23 +510,75 @@ func (s *AnalyzerService) shouldProcessAction(action string) bool {
 	return false
 }
 
-func (s *AnalyzerService) fetchPRDiff(ctx context.Context, diffURL string) (string, error) {
+// skippedDiffResponse answers a PR whose diff was not sent to Claude
+func skippedDiffResponse(payload models.GitHubPRPayload, status, summary string) *models.AnalysisResponse {
+	return &models.AnalysisResponse{
+		Summary:    summary,
+		Confidence: 1.0,
+		Repository: payload.Repository.FullName,
+		PRNumber:   payload.PullRequest.Number,
+		HeadSHA:    payload.PullRequest.Head.SHA,
+		PostmanUpdate: models.PostmanUpdate{
+			Status:    status,
+			UpdatedAt: time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// githubRepoURL returns the REST API URL of the PR's repository on the configured GitHub API, so
+// GitHub Enterprise Server works whatever the payload says. Without a repository name it falls back
+// to the PR's own API URL; "" means neither is known.
+func (s *AnalyzerService) githubRepoURL(payload models.GitHubPRPayload) string {
+	if payload.Repository.FullName != "" && s.config.GitHubAPIURL != "" {
+		return s.config.GitHubAPIURL + "/repos/" + payload.Repository.FullName
+	}
+	repoURL, ok := strings.CutSuffix(payload.PullRequest.URL, fmt.Sprintf("/pulls/%d", payload.PullRequest.Number))
+	if !ok {
+		return ""
+	}
+	return repoURL
+}
+
+// fetchPRDiff downloads the PR changes in the configured format. With a token the REST API is asked
+// for the diff media type; without one the public diff_url/patch_url is used, as github.com redirects
+// those to a host that rejects API credentials.
+func (s *AnalyzerService) fetchPRDiff(ctx context.Context, payload models.GitHubPRPayload) (string, error) {
+	pr := payload.PullRequest
+	mediaType := "application/vnd.github.diff"
+	diffURL := pr.DiffURL
+	if s.config.DiffFormat == config.DiffFormatPatch {
+		mediaType = "application/vnd.github.patch"
+		if pr.PatchURL != "" {
+			diffURL = pr.PatchURL
+		}
+	}
+
+	repoURL := s.githubRepoURL(payload)
+	token := s.gitHubToken()
+	authenticated := token != "" && repoURL != ""
+	if authenticated {
+		diffURL = fmt.Sprintf("%s/pulls/%d", repoURL, pr.Number)
+	}
 	if diffURL == "" {
 		return "", fmt.Errorf("diff URL is empty")
 	}
 
-	s.logger.Debug("Fetching PR diff", "diff_url", diffURL)
+	s.logger.Debug("Fetching PR diff", "diff_url", diffURL, "format", s.config.DiffFormat, "authenticated", authenticated)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", diffURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// GitHub returns plain text diff
-	req.Header.Set("Accept", "text/plain")
+	// The REST API negotiates on the media type; the plain-text fallbacks keep public URLs working
+	req.Header.Set("Accept", mediaType+", text/x-diff;q=0.9, text/plain;q=0.8")
+	if authenticated {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	}
 
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: s.config.DiffFetchTimeout,
 	}
 
 	resp, err := client.Do(req)
@@ -299,7 +591,18 @@ func (s *AnalyzerService) fetchPRDiff(ctx context.Context, diffURL string) (stri
 		return "", fmt.Errorf("failed to fetch diff, status: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// A JSON body means the API ignored the requested media type and returned the PR object instead
+	if contentType := resp.Header.Get("Content-Type"); strings.HasPrefix(contentType, "application/json") {
+		return "", fmt.Errorf("expected %s but GitHub returned %s", mediaType, contentType)
+	}
+
+	// Read one byte past the limit so oversized diffs are detected rather than silently truncated
+	var reader io.Reader = resp.Body
+	if s.config.MaxDiffBytes > 0 {
+		reader = io.LimitReader(resp.Body, int64(s.config.MaxDiffBytes)+1)
+	}
+
+	body, err := io.ReadAll(reader)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -313,8 +616,42 @@ func (s *AnalyzerService) fetchPRDiff(ctx context.Context, diffURL string) (stri
 	return diff, nil
 }
 
+// enforceRouteLimit truncates or rejects analyses returning more routes than MaxRoutes
+func (s *AnalyzerService) enforceRouteLimit(resp *models.AnalysisResponse) error {
+	limit := s.config.MaxRoutes
+	total := resp.RouteCount()
+	if limit <= 0 || total <= limit {
+		return nil
+	}
+
+	if s.config.MaxRoutesAction == config.MaxRoutesActionReject {
+		s.logger.Warn("Rejecting suspicious analysis with too many routes", "routes", total, "max_routes", limit)
+		return pkgerrors.NewValidationError(fmt.Sprintf("analysis returned %d routes, exceeding the limit of %d", total, limit)).
+			WithCode(pkgerrors.CodeTooManyRoutes).
+			WithContext("routes", total)
+	}
+
+	s.logger.Warn("Truncating analysis with too many routes", "routes", total, "max_routes", limit)
+
+	// Keep routes in priority order: new, then modified, then deleted
+	remaining := limit
+	truncate := func(routes []models.APIRoute) []models.APIRoute {
+		if len(routes) > remaining {
+			routes = routes[:remaining]
+		}
+		remaining -= len(routes)
+		return routes
+	}
+	resp.NewRoutes = truncate(resp.NewRoutes)
+	resp.ModifiedRoutes = truncate(resp.ModifiedRoutes)
+	resp.DeletedRoutes = truncate(resp.DeletedRoutes)
+	resp.Truncated = true
+
+	return nil
+}
+
 func (s *AnalyzerService) hasAPIChanges(resp *models.AnalysisResponse) bool {
-	return len(resp.NewRoutes) > 0 || len(resp.ModifiedRoutes) > 0 || len(resp.DeletedRoutes) > 0
+	return len(resp.NewRoutes) > 0 || len(resp.ModifiedRoutes) > 0 || len(resp.DeletedRoutes) > 0 || len(resp.Callbacks) > 0
 }
 
 // extractRoutesFromCollection extracts existing routes from Postman collection for context
@@ -352,11 +689,7 @@ func (s *AnalyzerService) extractRoutesFromItems(items []models.PostmanItem, fol
 func (s *AnalyzerService) extractPathFromURL(url models.PostmanURL) string {
 	if url.Raw != "" {
 		// Remove {{baseUrl}} and clean up the path
-		path := url.Raw
-		if len(path) > 0 && path[0:11] == "{{baseUrl}}" {
-			path = path[11:]
-		}
-		return path
+		return strings.TrimPrefix(url.Raw, "{{baseUrl}}")
 	}
 	
 	// Fallback to constructing from path segments
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// recoverDeferred logs a panic in an analysis running outside any request, such as a debounced
+// or queued one, so it can't take the whole server down. It must be deferred directly.
+func (s *AnalyzerService) recoverDeferred(what string, payload models.GitHubPRPayload) {
+	if r := recover(); r != nil {
+		s.logger.Error(what+" panicked", pkgerrors.NewInternalError("panic recovered"),
+			"pr_number", payload.PullRequest.Number,
+			"repo", payload.Repository.FullName,
+			"panic", r,
+		)
+	}
+}
+
+// dropDeferred stops scheduling analyses for later and logs every PR whose scheduled analysis
+// will not run, so they can be re-triggered after a restart
+func (s *AnalyzerService) dropDeferred() {
+	var dropped []models.GitHubPRPayload
+	if s.debouncer != nil {
+		dropped = append(dropped, s.debouncer.stop()...)
+	}
+	dropped = append(dropped, s.fallback.dropQueued()...)
+
+	for _, payload := range dropped {
+		s.logger.Warn("Dropping deferred PR analysis at shutdown",
+			"pr_number", payload.PullRequest.Number,
+			"repo", payload.Repository.FullName,
+			"action", payload.Action,
+		)
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/limiter"
+)
+
+// newBatchLimiter bounds how many batch analyses (queued retries, bulk runs) call Claude at once.
+// It is separate from interactive requests so a large batch can't starve them, and shrinks
+// below its configured size while Claude reports that the request quota is running out.
+func newBatchLimiter(max int, metrics interfaces.MetricsCollector) *limiter.Adaptive {
+	return limiter.New(max, func(limit, inUse int) {
+		metrics.SetGauge("batch_concurrency", float64(limit), map[string]string{"state": "limit"})
+		metrics.SetGauge("batch_concurrency", float64(inUse), map[string]string{"state": "in_use"})
+	})
+}
+
+// WatchClaudeRateLimits tunes the batch limiter to the request quota Claude reports
+func (s *AnalyzerService) WatchClaudeRateLimits(n interfaces.RateLimitNotifier) {
+	n.OnRateLimit(s.batch.Observe)
+}
+
+// runBatch runs every payload through run, at most as many at once as the batch limiter allows
+func (s *AnalyzerService) runBatch(payloads []models.GitHubPRPayload, run func(models.GitHubPRPayload)) {
+	var wg sync.WaitGroup
+	for _, payload := range payloads {
+		if err := s.batch.Acquire(context.Background()); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func(payload models.GitHubPRPayload) {
+			defer wg.Done()
+			defer s.batch.Release()
+			run(payload)
+		}(payload)
+	}
+	wg.Wait()
+}
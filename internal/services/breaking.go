@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// flagBreakingChanges adds the breaking changes that can be told from the analysis itself to those
+// reported by Claude: removal of documented routes and stricter auth on modified ones. Deprecated
+// routes that are still served don't count.
+func (s *AnalyzerService) flagBreakingChanges(resp *models.AnalysisResponse, existing []models.ExistingRoute) {
+	documented := make(map[string]bool, len(existing))
+	for _, route := range existing {
+		documented[breakingRouteKey(route.Method, route.Path)] = true
+	}
+
+	var computed []string
+	for _, route := range resp.DeletedRoutes {
+		if route.Deprecated && !route.Removed {
+			continue
+		}
+		if len(documented) > 0 && !documented[breakingRouteKey(route.Method, route.Path)] {
+			continue
+		}
+		computed = append(computed, fmt.Sprintf("%s %s was removed", strings.ToUpper(route.Method), route.Path))
+	}
+	for _, route := range resp.ModifiedRoutes {
+		if route.Auth == nil || !route.Auth.Changed || route.Auth.Scheme == models.AuthSchemeNone {
+			continue
+		}
+		change := fmt.Sprintf("%s %s now requires %s auth", strings.ToUpper(route.Method), route.Path, route.Auth.Scheme)
+		if route.Auth.Note != "" {
+			change += " (" + route.Auth.Note + ")"
+		}
+		computed = append(computed, change)
+	}
+
+	resp.BreakingChanges = mergeBreakingChanges(resp.BreakingChanges, computed)
+	if len(resp.BreakingChanges) > 0 {
+		s.logger.Warn("Breaking API changes detected",
+			"pr_number", resp.PRNumber,
+			"breaking_changes", len(resp.BreakingChanges),
+		)
+	}
+}
+
+// mergeBreakingChanges appends computed entries unless Claude already reported the same route
+func mergeBreakingChanges(reported, computed []string) []string {
+	merged := make([]string, 0, len(reported)+len(computed))
+	for _, change := range reported {
+		if change = strings.TrimSpace(change); change != "" {
+			merged = append(merged, change)
+		}
+	}
+
+	for _, change := range computed {
+		// "METHOD /path" is the first two words of every computed entry
+		route := strings.Join(strings.Fields(change)[:2], " ")
+		duplicate := false
+		for _, existing := range merged {
+			if strings.Contains(existing, route) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, change)
+		}
+	}
+	return merged
+}
+
+func breakingRouteKey(method, path string) string {
+	return strings.ToUpper(method) + " " + strings.TrimSuffix(strings.TrimPrefix(path, "{{baseUrl}}"), "/")
+}
+
+// breakingChangesSection is placed at the top of the summary so reviewers see it first
+func breakingChangesSection(changes []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BREAKING CHANGES (%d):\n", len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(&b, "- %s\n", change)
+	}
+	return b.String()
+}
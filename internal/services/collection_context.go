@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// collectionContext is the result of loading one collection for context
+type collectionContext struct {
+	collectionID string
+	collection   *models.PostmanCollection
+	err          error
+}
+
+// loadExistingRoutes fetches the target collection and every ContextCollectionIDs collection
+// concurrently and merges their routes, target first. Only an unreachable explicit target
+// override is an error; other failures just leave that collection out of the context.
+func (s *AnalyzerService) loadExistingRoutes(ctx context.Context, payload models.GitHubPRPayload) ([]models.ExistingRoute, error) {
+	// Without an override the target is the default collection, which must not be fetched twice
+	targetID := payload.CollectionID
+	if targetID == "" {
+		targetID = s.config.DefaultCollectionID
+	}
+	ids := []string{payload.CollectionID}
+	queued := map[string]bool{targetID: true}
+	for _, id := range s.config.ContextCollectionIDs {
+		if !queued[id] {
+			queued[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	results := make([]collectionContext, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			collection, err := s.postmanClient.GetCollection(ctx, id)
+			results[i] = collectionContext{collectionID: id, collection: collection, err: err}
+		}(i, id)
+	}
+	wg.Wait()
+
+	target := results[0]
+	if target.err != nil {
+		// An explicit collection override must be reachable, otherwise the update would fail later anyway
+		if payload.CollectionID != "" {
+			s.logger.Error("Cannot access override collection", target.err, "collection_id", payload.CollectionID)
+			return nil, fmt.Errorf("cannot access collection %s: %w", payload.CollectionID, target.err)
+		}
+		s.logger.Warn("Failed to get existing collection context", "error", target.err)
+		// Continue without context - don't fail the entire operation
+	}
+
+	var routes []models.ExistingRoute
+	seen := make(map[string]bool)
+	for i, result := range results {
+		if result.err != nil {
+			if i > 0 {
+				s.logger.Warn("Failed to load context collection, continuing without it", "collection_id", result.collectionID, "error", result.err)
+			}
+			continue
+		}
+		if result.collection == nil {
+			continue
+		}
+
+		for _, route := range s.extractRoutesFromCollection(result.collection) {
+			key := strings.ToUpper(route.Method) + " " + route.Path
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			// Routes from other collections are shown under the collection's name
+			if i > 0 {
+				route.FolderPath = append([]string{result.collection.Info.Name}, route.FolderPath...)
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// applyRouteConfidence replaces the overall confidence with the aggregate of per-route scores
+// and, when MinRouteConfidence is set, moves routes below it into FlaggedRoutes so they are
+// reported for review but not applied to Postman.
+func (s *AnalyzerService) applyRouteConfidence(resp *models.AnalysisResponse) {
+	overall := resp.Confidence
+	resp.Confidence = resp.AggregateConfidence()
+
+	threshold := s.config.MinRouteConfidence
+	if threshold <= 0 {
+		return
+	}
+
+	split := func(routes []models.APIRoute) []models.APIRoute {
+		kept := routes[:0]
+		for _, route := range routes {
+			if route.EffectiveConfidence(overall) < threshold {
+				resp.FlaggedRoutes = append(resp.FlaggedRoutes, route)
+				continue
+			}
+			kept = append(kept, route)
+		}
+		return kept
+	}
+	resp.NewRoutes = split(resp.NewRoutes)
+	resp.ModifiedRoutes = split(resp.ModifiedRoutes)
+	resp.DeletedRoutes = split(resp.DeletedRoutes)
+
+	if len(resp.FlaggedRoutes) > 0 {
+		s.logger.Warn("Holding back low-confidence routes for review",
+			"flagged_routes", len(resp.FlaggedRoutes),
+			"min_route_confidence", threshold,
+			"pr_number", resp.PRNumber,
+		)
+	}
+}
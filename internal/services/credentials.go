@@ -0,0 +1,12 @@
+package services
+
+// SetGitHubToken replaces the GitHub token used for diff and file content requests, e.g. after
+// the secret manager rotated it
+func (s *AnalyzerService) SetGitHubToken(token string) {
+	s.githubToken.Store(token)
+}
+
+func (s *AnalyzerService) gitHubToken() string {
+	token, _ := s.githubToken.Load().(string)
+	return token
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// skipDebounceKey marks contexts of analyses scheduled by the debouncer itself
+type skipDebounceKey struct{}
+
+// prDebounceState tracks the last analysis of a PR and any analysis scheduled after its cooldown
+type prDebounceState struct {
+	lastAnalysis time.Time
+	pending      *models.GitHubPRPayload
+	timer        *time.Timer
+}
+
+// prDebouncer coalesces synchronize events arriving within the cooldown of the last analysis
+// of the same PR into a single analysis of the latest head once the cooldown expires.
+// Every scheduled analysis counts in background until it has run or been dropped by stop.
+type prDebouncer struct {
+	mu         sync.Mutex
+	cooldown   time.Duration
+	states     map[string]*prDebounceState
+	background *sync.WaitGroup
+	stopped    bool
+}
+
+func newPRDebouncer(cooldown time.Duration, background *sync.WaitGroup) *prDebouncer {
+	return &prDebouncer{
+		cooldown:   cooldown,
+		states:     make(map[string]*prDebounceState),
+		background: background,
+	}
+}
+
+func debounceKey(payload models.GitHubPRPayload) string {
+	return fmt.Sprintf("%s#%d", payload.Repository.FullName, payload.PullRequest.Number)
+}
+
+// debounce records the analysis of payload and reports whether it was deferred instead.
+// run is called with the latest deferred payload once the cooldown has passed.
+func (d *prDebouncer) debounce(payload models.GitHubPRPayload, run func(models.GitHubPRPayload)) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	d.prune(now)
+
+	key := debounceKey(payload)
+	state, ok := d.states[key]
+	if !ok {
+		state = &prDebounceState{}
+		d.states[key] = state
+	}
+
+	if payload.Action != "synchronize" || d.stopped {
+		state.lastAnalysis = now
+		return false
+	}
+
+	// An analysis is already scheduled: it will pick up this newer head instead
+	if state.timer != nil {
+		state.pending = &payload
+		return true
+	}
+
+	elapsed := now.Sub(state.lastAnalysis)
+	if elapsed >= d.cooldown {
+		state.lastAnalysis = now
+		return false
+	}
+
+	state.pending = &payload
+	d.background.Add(1)
+	state.timer = time.AfterFunc(d.cooldown-elapsed, func() {
+		defer d.background.Done()
+
+		d.mu.Lock()
+		latest := state.pending
+		state.pending = nil
+		state.timer = nil
+		state.lastAnalysis = time.Now()
+		d.mu.Unlock()
+
+		if latest != nil {
+			run(*latest)
+		}
+	})
+	return true
+}
+
+// stop cancels every scheduled analysis and returns the payloads it dropped. Later events are
+// no longer deferred.
+func (d *prDebouncer) stop() []models.GitHubPRPayload {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopped = true
+	var dropped []models.GitHubPRPayload
+	for _, state := range d.states {
+		// A timer that already fired is running its analysis, which background still tracks
+		if state.timer == nil || !state.timer.Stop() {
+			continue
+		}
+		d.background.Done()
+		if state.pending != nil {
+			dropped = append(dropped, *state.pending)
+		}
+		state.pending = nil
+		state.timer = nil
+	}
+	return dropped
+}
+
+// prune drops idle PR state that is past its cooldown. Callers must hold d.mu.
+func (d *prDebouncer) prune(now time.Time) {
+	for key, state := range d.states {
+		if state.timer == nil && now.Sub(state.lastAnalysis) >= d.cooldown {
+			delete(d.states, key)
+		}
+	}
+}
+
+// debounceAnalysis defers synchronize events within the PR cooldown, returning a response
+// describing the deferral when it does
+func (s *AnalyzerService) debounceAnalysis(ctx context.Context, payload models.GitHubPRPayload) (*models.AnalysisResponse, bool) {
+	if s.debouncer == nil || ctx.Value(skipDebounceKey{}) != nil {
+		return nil, false
+	}
+
+	deferred := s.debouncer.debounce(payload, func(latest models.GitHubPRPayload) {
+		defer s.recoverDeferred("Debounced PR analysis", latest)
+
+		runCtx := context.WithValue(context.Background(), skipDebounceKey{}, true)
+		if s.config.AnalysisTimeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(runCtx, s.config.AnalysisTimeout)
+			defer cancel()
+		}
+
+		s.logger.Info("Running debounced PR analysis",
+			"pr_number", latest.PullRequest.Number,
+			"repo", latest.Repository.FullName,
+		)
+		if _, err := s.AnalyzePR(runCtx, latest); err != nil {
+			s.logger.Error("Debounced PR analysis failed", err,
+				"pr_number", latest.PullRequest.Number,
+				"repo", latest.Repository.FullName,
+			)
+		}
+	})
+	if !deferred {
+		return nil, false
+	}
+
+	s.logger.Info("Coalescing rapid push into a scheduled analysis",
+		"pr_number", payload.PullRequest.Number,
+		"repo", payload.Repository.FullName,
+		"cooldown", s.config.PRCooldown.String(),
+	)
+	return &models.AnalysisResponse{
+		Repository: payload.Repository.FullName,
+		PRNumber:   payload.PullRequest.Number,
+		Summary:    fmt.Sprintf("Analysis deferred: pushes within %s are coalesced into one analysis", s.config.PRCooldown),
+		PostmanUpdate: models.PostmanUpdate{
+			Status:    "debounced",
+			UpdatedAt: time.Now().Format(time.RFC3339),
+		},
+	}, true
+}
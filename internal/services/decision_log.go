@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// analysisDecision collects every decision made while analyzing one PR, so the whole
+// decision path is logged as a single queryable "analysis_complete" entry
+type analysisDecision struct {
+	started       time.Time
+	repository    string
+	prNumber      int
+	action        string
+	actor         string // GitHub sender, or the entry point when there is none
+	source        string
+	skipReason    string // action, draft, debounced, description, empty_diff, diff_too_small or breaker_open; empty when the diff was analyzed
+	diffSizeBytes int
+	contextRoutes int
+}
+
+func newAnalysisDecision(payload models.GitHubPRPayload) *analysisDecision {
+	return &analysisDecision{
+		started:    time.Now(),
+		repository: payload.Repository.FullName,
+		prNumber:   payload.PullRequest.Number,
+		action:     payload.Action,
+		actor:      payloadActor(payload),
+		source:     payload.Source,
+	}
+}
+
+func payloadActor(payload models.GitHubPRPayload) string {
+	if payload.Sender.Login != "" {
+		return payload.Sender.Login
+	}
+	if payload.Source != "" {
+		return payload.Source
+	}
+	return models.PayloadSourceWebhook
+}
+
+// skip records why the PR was not analyzed
+func (d *analysisDecision) skip(reason string) {
+	d.skipReason = reason
+}
+
+// logDecision emits the analysis_complete entry. Every field is always present so entries
+// share one schema regardless of where the analysis stopped.
+func (s *AnalyzerService) logDecision(d *analysisDecision, resp *models.AnalysisResponse, err error) {
+	outcome := "success"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case d.skipReason != "":
+		outcome = "skipped"
+	case resp != nil && resp.PostmanUpdate.Failed():
+		outcome = "partial"
+	}
+
+	var (
+		newRoutes, modifiedRoutes, deletedRoutes, flaggedRoutes int
+		inputTokens, outputTokens                               int
+		confidence                                              float64
+		updateMode, postmanStatus, errorCode                    string
+	)
+	if resp != nil {
+		newRoutes = len(resp.NewRoutes)
+		modifiedRoutes = len(resp.ModifiedRoutes)
+		deletedRoutes = len(resp.DeletedRoutes)
+		flaggedRoutes = len(resp.FlaggedRoutes)
+		confidence = resp.Confidence
+		updateMode = resp.PostmanUpdate.UpdateMode
+		postmanStatus = resp.PostmanUpdate.Status
+		errorCode = resp.PostmanUpdate.ErrorCode
+		if resp.Usage != nil {
+			inputTokens = resp.Usage.InputTokens
+			outputTokens = resp.Usage.OutputTokens
+		}
+	}
+	if err != nil {
+		errorCode = pkgerrors.CodeOf(err)
+	}
+
+	s.logger.Info("analysis_complete",
+		"repo", d.repository,
+		"pr_number", d.prNumber,
+		"action", d.action,
+		"outcome", outcome,
+		"processed", d.skipReason == "",
+		"skip_reason", d.skipReason,
+		"diff_size_bytes", d.diffSizeBytes,
+		"context_routes", d.contextRoutes,
+		"new_routes", newRoutes,
+		"modified_routes", modifiedRoutes,
+		"deleted_routes", deletedRoutes,
+		"flagged_routes", flaggedRoutes,
+		"confidence", confidence,
+		"input_tokens", inputTokens,
+		"output_tokens", outputTokens,
+		"update_mode", updateMode,
+		"postman_status", postmanStatus,
+		"error_code", errorCode,
+		"duration_ms", time.Since(d.started).Milliseconds(),
+	)
+
+	source := d.source
+	if source == "" {
+		source = models.PayloadSourceWebhook
+	}
+	s.events.Emit(models.Event{
+		Type:       models.EventAnalysisCompleted,
+		Repository: d.repository,
+		PRNumber:   d.prNumber,
+		Source:     source,
+		Data: map[string]any{
+			"outcome":         outcome,
+			"skip_reason":     d.skipReason,
+			"new_routes":      newRoutes,
+			"modified_routes": modifiedRoutes,
+			"deleted_routes":  deletedRoutes,
+			"confidence":      confidence,
+			"input_tokens":    inputTokens,
+			"output_tokens":   outputTokens,
+			"postman_status":  postmanStatus,
+			"error_code":      errorCode,
+			"duration_ms":     time.Since(d.started).Milliseconds(),
+		},
+	})
+
+	if s.audit != nil {
+		auditOutcome := models.AuditOutcomeSuccess
+		switch outcome {
+		case "error", "partial":
+			auditOutcome = models.AuditOutcomeFailure
+		case "skipped":
+			auditOutcome = models.AuditOutcomeSkipped
+		}
+		s.audit.Record(models.AuditEvent{
+			Action:   models.AuditActionAnalysis,
+			Actor:    d.actor,
+			Resource: fmt.Sprintf("github:%s#%d", d.repository, d.prNumber),
+			Outcome:  auditOutcome,
+			Details: map[string]any{
+				"skip_reason":     d.skipReason,
+				"new_routes":      newRoutes,
+				"modified_routes": modifiedRoutes,
+				"deleted_routes":  deletedRoutes,
+				"postman_status":  postmanStatus,
+				"error_code":      errorCode,
+			},
+		})
+	}
+}
+
+// SetAuditLogger records every analysis outcome to audit
+func (s *AnalyzerService) SetAuditLogger(audit interfaces.AuditLogger) {
+	s.audit = audit
+}
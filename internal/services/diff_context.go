@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// maxContextFiles caps the files fetched to expand diff context, one GitHub request each
+const maxContextFiles = 50
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// hunk is one "@@" section of a file diff, with line numbers as in its header
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	section            string // text after the closing @@, usually the enclosing function
+	lines              []string
+}
+
+// expandDiffContext widens every hunk to lines of context by reading the head revision of each
+// changed file from GitHub. Files that can't be fetched keep GitHub's context; the diff is
+// returned unchanged when no token is configured or GitHub's context is enough.
+func (s *AnalyzerService) expandDiffContext(ctx context.Context, payload models.GitHubPRPayload, diff string) string {
+	lines := s.config.DiffContextLinesFor(payload.Repository.FullName)
+	extra := lines - config.GitHubDiffContextLines
+	if extra <= 0 {
+		return diff
+	}
+	repoURL := s.githubRepoURL(payload)
+	if s.gitHubToken() == "" || repoURL == "" || payload.PullRequest.Head.SHA == "" {
+		s.logger.Debug("Cannot expand diff context without a GitHub token and PR API URL", "pr_number", payload.PullRequest.Number)
+		return diff
+	}
+
+	var b strings.Builder
+	expanded, fetched := 0, 0
+	for _, file := range splitDiffFiles(diff) {
+		path, ok := newFilePath(file)
+		if !ok || fetched == maxContextFiles {
+			b.WriteString(file)
+			continue
+		}
+
+		fetched++
+		content, err := s.fetchFileContent(ctx, repoURL, path, payload.PullRequest.Head.SHA)
+		if err != nil {
+			s.logger.Warn("Failed to fetch file for diff context, keeping GitHub's context", "path", path, "error", err)
+			b.WriteString(file)
+			continue
+		}
+
+		b.WriteString(expandFileHunks(file, strings.Split(content, "\n"), extra))
+		expanded++
+	}
+
+	s.logger.Info("Expanded diff context",
+		"pr_number", payload.PullRequest.Number,
+		"context_lines", lines,
+		"files_expanded", expanded,
+		"diff_size_bytes", b.Len(),
+	)
+	return b.String()
+}
+
+// fetchFileContent reads path at ref through the contents API
+func (s *AnalyzerService) fetchFileContent(ctx context.Context, repoURL, path, ref string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	contentURL := fmt.Sprintf("%s/contents/%s?ref=%s", repoURL, strings.Join(segments, "/"), url.QueryEscape(ref))
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.DiffFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, contentURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	req.Header.Set("Authorization", "Bearer "+s.gitHubToken())
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch file, status: %d", resp.StatusCode)
+	}
+
+	var reader io.Reader = resp.Body
+	if s.config.MaxDiffBytes > 0 {
+		reader = io.LimitReader(resp.Body, int64(s.config.MaxDiffBytes))
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return string(body), nil
+}
+
+// splitDiffFiles splits a git diff before every "diff --git" header
+func splitDiffFiles(diff string) []string {
+	var files []string
+	for diff != "" {
+		next := strings.Index(diff, "\ndiff --git ")
+		if next < 0 {
+			files = append(files, diff)
+			break
+		}
+		files = append(files, diff[:next+1])
+		diff = diff[next+1:]
+	}
+	return files
+}
+
+// newFilePath returns the path of the file after the change; deleted files have none
+func newFilePath(file string) (string, bool) {
+	for _, line := range strings.Split(file, "\n") {
+		if strings.HasPrefix(line, "+++ ") {
+			path := strings.TrimPrefix(strings.TrimSpace(line[4:]), "b/")
+			return path, path != "/dev/null"
+		}
+		if strings.HasPrefix(line, "@@") {
+			break
+		}
+	}
+	return "", false
+}
+
+// expandFileHunks adds up to extra lines of context before and after every hunk of file, taking
+// them from content, the file after the change. Context never overlaps a neighbouring hunk.
+func expandFileHunks(file string, content []string, extra int) string {
+	header, hunks := parseHunks(file)
+	if len(hunks) == 0 {
+		return file
+	}
+
+	// A file that doesn't match the hunks (e.g. changed since) is left alone
+	content = trimFinalNewline(content)
+	for _, h := range hunks {
+		if h.newStart+h.newCount-1 > len(content) {
+			return file
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	prevEnd := 0 // last new-file line already written
+	for i, h := range hunks {
+		end := h.newStart + h.newCount - 1
+		nextStart := len(content) + 1
+		if i+1 < len(hunks) {
+			nextStart = hunks[i+1].newStart
+		}
+
+		before := max(min(extra, h.newStart-1-prevEnd), 0)
+		after := max(min(h.after(extra), nextStart-1-end, len(content)-end), 0)
+		if h.newCount == 0 {
+			before, after = 0, 0 // pure deletions have no anchor in the new file
+		}
+		prevEnd = end + after
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@%s\n",
+			h.oldStart-before, h.oldCount+before+after, h.newStart-before, h.newCount+before+after, h.section)
+		for _, line := range content[h.newStart-1-before : h.newStart-1] {
+			b.WriteString(" " + line + "\n")
+		}
+		for _, line := range h.lines {
+			b.WriteString(line + "\n")
+		}
+		for _, line := range content[end : end+after] {
+			b.WriteString(" " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// trimFinalNewline drops the empty element splitting a newline-terminated file leaves behind
+func trimFinalNewline(content []string) []string {
+	if n := len(content); n > 0 && content[n-1] == "" {
+		return content[:n-1]
+	}
+	return content
+}
+
+// after is the context that may follow the hunk; none once the file ends without a newline
+func (h hunk) after(extra int) int {
+	if len(h.lines) > 0 && strings.HasPrefix(h.lines[len(h.lines)-1], `\`) {
+		return 0
+	}
+	return extra
+}
+
+// parseHunks splits a file diff into its header lines and hunks
+func parseHunks(file string) (string, []hunk) {
+	var header strings.Builder
+	var hunks []hunk
+	for _, line := range strings.Split(strings.TrimSuffix(file, "\n"), "\n") {
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			hunks = append(hunks, hunk{
+				oldStart: atoiDefault(m[1], 1), oldCount: atoiDefault(m[2], 1),
+				newStart: atoiDefault(m[3], 1), newCount: atoiDefault(m[4], 1),
+				section: m[5],
+			})
+			continue
+		}
+		if len(hunks) == 0 {
+			header.WriteString(line + "\n")
+			continue
+		}
+		hunks[len(hunks)-1].lines = append(hunks[len(hunks)-1].lines, line)
+	}
+	return header.String(), hunks
+}
+
+func atoiDefault(value string, fallback int) int {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	return fallback
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+)
+
+// DiffTransformerChain applies registered diff transformers in registration order, logging how
+// many bytes each one removed
+type DiffTransformerChain struct {
+	transformers []interfaces.DiffTransformer
+	logger       interfaces.Logger
+}
+
+// NewDiffTransformerChain creates a diff transformer chain with the given transformers
+func NewDiffTransformerChain(logger interfaces.Logger, transformers ...interfaces.DiffTransformer) *DiffTransformerChain {
+	return &DiffTransformerChain{transformers: transformers, logger: logger}
+}
+
+// Register appends a transformer to the end of the chain
+func (c *DiffTransformerChain) Register(t interfaces.DiffTransformer) {
+	c.transformers = append(c.transformers, t)
+}
+
+// Len returns the number of registered transformers
+func (c *DiffTransformerChain) Len() int {
+	return len(c.transformers)
+}
+
+// Transform runs every transformer, feeding each one the output of the previous
+func (c *DiffTransformerChain) Transform(ctx context.Context, diff string) (string, error) {
+	for _, t := range c.transformers {
+		out, err := t.TransformDiff(ctx, diff)
+		if err != nil {
+			return "", fmt.Errorf("diff transformer %s failed: %w", t.Name(), err)
+		}
+		c.logger.Info("Applied diff transformer",
+			"transformer", t.Name(),
+			"bytes_before", len(diff),
+			"bytes_after", len(out),
+			"bytes_removed", len(diff)-len(out),
+		)
+		diff = out
+	}
+	return diff, nil
+}
+
+// NewDiffTransformers builds the configured DIFF_TRANSFORMERS, in order
+func NewDiffTransformers(cfg config.AnalyzerConfig) ([]interfaces.DiffTransformer, error) {
+	transformers := make([]interfaces.DiffTransformer, 0, len(cfg.DiffTransformers))
+	for _, name := range cfg.DiffTransformers {
+		switch name {
+		case config.DiffTransformerStripComments:
+			transformers = append(transformers, StripCommentsTransformer{})
+		case config.DiffTransformerCollapseWhitespace:
+			transformers = append(transformers, CollapseWhitespaceTransformer{})
+		case config.DiffTransformerFilterPaths:
+			t, err := NewFilterPathsTransformer(cfg.DiffExcludePaths)
+			if err != nil {
+				return nil, err
+			}
+			transformers = append(transformers, t)
+		default:
+			return nil, fmt.Errorf("unknown diff transformer %q", name)
+		}
+	}
+	return transformers, nil
+}
+
+// diffLineBody splits a diff line into its +, - or space marker and content. Headers and hunk
+// lines report ok=false and are never rewritten.
+func diffLineBody(line string) (marker, body string, ok bool) {
+	if line == "" || strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ") {
+		return "", "", false
+	}
+	switch line[0] {
+	case '+', '-', ' ':
+		return line[:1], line[1:], true
+	}
+	return "", "", false
+}
+
+// commentLine matches lines holding only a comment in the usual C-style, shell-style, SQL and
+// HTML syntaxes. Block comment bodies are matched by their leading "*".
+var commentLine = regexp.MustCompile(`^\s*(//|#($|[^\[!])|--\s|/\*|\*($|\s|/)|<!--)`)
+
+// StripCommentsTransformer drops diff lines that only hold a comment. Route annotations living in
+// comments (e.g. swag's // @Router) are kept.
+type StripCommentsTransformer struct{}
+
+// Name identifies the transformer in logs
+func (StripCommentsTransformer) Name() string {
+	return config.DiffTransformerStripComments
+}
+
+// TransformDiff removes comment-only lines
+func (StripCommentsTransformer) TransformDiff(ctx context.Context, diff string) (string, error) {
+	lines := strings.Split(diff, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if _, body, ok := diffLineBody(line); ok && commentLine.MatchString(body) && !strings.Contains(body, "@") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+var whitespaceRun = regexp.MustCompile(`[ \t]+`)
+
+// CollapseWhitespaceTransformer collapses indentation and runs of spaces to one space and drops
+// lines that are blank apart from their marker
+type CollapseWhitespaceTransformer struct{}
+
+// Name identifies the transformer in logs
+func (CollapseWhitespaceTransformer) Name() string {
+	return config.DiffTransformerCollapseWhitespace
+}
+
+// TransformDiff rewrites every diff line with collapsed whitespace
+func (CollapseWhitespaceTransformer) TransformDiff(ctx context.Context, diff string) (string, error) {
+	lines := strings.Split(diff, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		marker, body, ok := diffLineBody(line)
+		if !ok {
+			kept = append(kept, line)
+			continue
+		}
+		body = strings.TrimSpace(whitespaceRun.ReplaceAllString(body, " "))
+		if body == "" {
+			continue
+		}
+		kept = append(kept, marker+body)
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+// FilterPathsTransformer drops the diffs of files whose path matches any exclude regex
+type FilterPathsTransformer struct {
+	exclude []*regexp.Regexp
+}
+
+// NewFilterPathsTransformer creates a transformer dropping files matching the exclude regexes
+func NewFilterPathsTransformer(exclude []string) (*FilterPathsTransformer, error) {
+	patterns, err := compilePathPatterns(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diff exclude path: %w", err)
+	}
+	return &FilterPathsTransformer{exclude: patterns}, nil
+}
+
+// Name identifies the transformer in logs
+func (t *FilterPathsTransformer) Name() string {
+	return config.DiffTransformerFilterPaths
+}
+
+// TransformDiff removes excluded files from the diff
+func (t *FilterPathsTransformer) TransformDiff(ctx context.Context, diff string) (string, error) {
+	var b strings.Builder
+	for _, file := range splitDiffFiles(diff) {
+		if path := diffHeaderPath(file); path != "" && matchesAny(t.exclude, path) {
+			continue
+		}
+		b.WriteString(file)
+	}
+	return b.String(), nil
+}
+
+// diffHeaderPath returns the path after the change from a file's "diff --git a/x b/y" header,
+// which deleted files keep as well
+func diffHeaderPath(file string) string {
+	header, _, _ := strings.Cut(file, "\n")
+	if !strings.HasPrefix(header, "diff --git ") {
+		return ""
+	}
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return header[i+3:]
+	}
+	return ""
+}
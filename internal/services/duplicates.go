@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/pathutil"
+)
+
+// collapseDuplicateRoutes removes routes reported more than once in the same list (e.g. once
+// from the handler and once from the router), keeping the richest one at the position of the
+// first occurrence
+func (s *AnalyzerService) collapseDuplicateRoutes(resp *models.AnalysisResponse) {
+	var collapsed int
+	resp.NewRoutes, collapsed = collapseRoutes(resp.NewRoutes)
+	total := collapsed
+	resp.ModifiedRoutes, collapsed = collapseRoutes(resp.ModifiedRoutes)
+	total += collapsed
+	resp.DeletedRoutes, collapsed = collapseRoutes(resp.DeletedRoutes)
+	total += collapsed
+
+	if total > 0 {
+		s.logger.Info("Collapsed duplicate routes in analysis",
+			"pr_number", resp.PRNumber,
+			"collapsed", total,
+		)
+	}
+}
+
+// collapseRoutes de-duplicates routes by method and normalized path, returning how many were dropped
+func collapseRoutes(routes []models.APIRoute) ([]models.APIRoute, int) {
+	if len(routes) < 2 {
+		return routes, 0
+	}
+
+	index := make(map[string]int, len(routes))
+	kept := make([]models.APIRoute, 0, len(routes))
+	for _, route := range routes {
+		key := strings.ToUpper(strings.TrimSpace(route.Method)) + " " + pathutil.Normalize(route.Path)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(kept)
+			kept = append(kept, route)
+			continue
+		}
+		if routeRichness(route) > routeRichness(kept[i]) {
+			kept[i] = route
+		}
+	}
+	return kept, len(routes) - len(kept)
+}
+
+// routeRichness ranks duplicates: the longer description wins, then the more detailed route
+func routeRichness(route models.APIRoute) int {
+	return len(strings.TrimSpace(route.Description))*100 + len(route.Parameters) + len(route.Responses) + len(route.Headers)
+}
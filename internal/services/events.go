@@ -0,0 +1,40 @@
+package services
+
+import (
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// nopEventEmitter is used until SetEventEmitter is called
+type nopEventEmitter struct{}
+
+func (nopEventEmitter) Emit(models.Event) {}
+
+// SetEventEmitter publishes analysis lifecycle events to emitter
+func (s *AnalyzerService) SetEventEmitter(emitter interfaces.EventEmitter) {
+	s.events = emitter
+}
+
+// emitEvent publishes a lifecycle event about the PR in payload
+func (s *AnalyzerService) emitEvent(eventType string, payload models.GitHubPRPayload, data map[string]any) {
+	source := payload.Source
+	if source == "" {
+		source = models.PayloadSourceWebhook
+	}
+	s.events.Emit(models.Event{
+		Type:       eventType,
+		Repository: payload.Repository.FullName,
+		PRNumber:   payload.PullRequest.Number,
+		Source:     source,
+		Data:       data,
+	})
+}
+
+// routeKeys lists routes as "METHOD /path" for events
+func routeKeys(routes []models.APIRoute) []string {
+	keys := make([]string, 0, len(routes))
+	for _, route := range routes {
+		keys = append(keys, route.Method+" "+route.Path)
+	}
+	return keys
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// breakerPollInterval is how often queued analyses check whether the Claude breaker has recovered.
+// gobreaker only leaves the open state when it is consulted, so transitions alone are not enough.
+const breakerPollInterval = 15 * time.Second
+
+// The cache fallback keeps the last analysis of this many recently analyzed PRs, for this long
+const (
+	lastAnalysisMaxEntries = 1000
+	lastAnalysisTTL        = 24 * time.Hour
+)
+
+// breakerFallback handles analyses that fail because the Claude circuit breaker is open, either
+// queueing them until the breaker recovers or answering with the PR's last known analysis.
+// Manual analyses all share one synthetic PR, so they never use a fallback.
+type breakerFallback struct {
+	mode    string
+	breaker interfaces.CircuitBreaker
+
+	mu      sync.Mutex
+	queued  map[string]models.GitHubPRPayload // latest payload per PR, with the diff already fetched
+	last    *analysisCache                    // last successful analysis per PR, LRU and TTL bounded
+	running bool
+	stopped bool
+	wake    chan struct{}
+	stop    chan struct{}
+}
+
+func newBreakerFallback(mode string) *breakerFallback {
+	return &breakerFallback{
+		mode:   mode,
+		queued: make(map[string]models.GitHubPRPayload),
+		last:   newAnalysisCache(lastAnalysisTTL, lastAnalysisMaxEntries),
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// WatchClaudeBreaker ties the breaker fallback to the Claude circuit breaker, so queued
+// analyses are retried as soon as it leaves the open state
+func (s *AnalyzerService) WatchClaudeBreaker(cb interfaces.CircuitBreaker) {
+	s.fallback.breaker = cb
+	if notifier, ok := cb.(interfaces.StateChangeNotifier); ok {
+		// Called with the breaker locked: only signal, never consult the breaker here
+		notifier.OnStateChange(func(from, to string) {
+			if from == "open" {
+				select {
+				case s.fallback.wake <- struct{}{}:
+				default:
+				}
+			}
+		})
+	}
+}
+
+// remember keeps the latest successful analysis of a PR for the cache fallback
+func (f *breakerFallback) remember(payload models.GitHubPRPayload, resp *models.AnalysisResponse) {
+	if f.mode != config.BreakerFallbackCache || payload.Source == models.PayloadSourceManual {
+		return
+	}
+	f.last.put(debounceKey(payload), payload.CollectionID, resp)
+}
+
+// handleBreakerOpen applies the configured fallback when err means the Claude breaker is open.
+// It returns false when the error should be returned as is.
+func (s *AnalyzerService) handleBreakerOpen(payload models.GitHubPRPayload, err error) (*models.AnalysisResponse, bool) {
+	if pkgerrors.CodeOf(err) != pkgerrors.CodeClaudeCircuitOpen || payload.Source == models.PayloadSourceManual {
+		return nil, false
+	}
+
+	f := s.fallback
+	key := debounceKey(payload)
+
+	switch f.mode {
+	case config.BreakerFallbackQueue:
+		f.mu.Lock()
+		if f.stopped {
+			f.mu.Unlock()
+			return nil, false
+		}
+		f.queued[key] = payload
+		if !f.running {
+			f.running = true
+			s.background.Add(1)
+			go s.retryQueued()
+		}
+		f.mu.Unlock()
+
+		s.logger.Warn("Claude circuit breaker open, queueing PR analysis for retry",
+			"pr_number", payload.PullRequest.Number,
+			"repo", payload.Repository.FullName,
+		)
+		s.metrics.IncrementCounter("breaker_fallback_total", map[string]string{"mode": f.mode})
+		return &models.AnalysisResponse{
+			Repository: payload.Repository.FullName,
+			PRNumber:   payload.PullRequest.Number,
+			Summary:    "Claude is temporarily unavailable; analysis queued and will run once it recovers",
+			PostmanUpdate: models.PostmanUpdate{
+				Status:    "queued",
+				UpdatedAt: time.Now().Format(time.RFC3339),
+			},
+		}, true
+
+	case config.BreakerFallbackCache:
+		cached, ok := f.last.get(key)
+		if !ok {
+			return nil, false
+		}
+
+		s.logger.Warn("Claude circuit breaker open, returning last known analysis",
+			"pr_number", payload.PullRequest.Number,
+			"repo", payload.Repository.FullName,
+		)
+		s.metrics.IncrementCounter("breaker_fallback_total", map[string]string{"mode": f.mode})
+		resp := *cached
+		resp.Summary = fmt.Sprintf("Claude is temporarily unavailable; showing the last known analysis. %s", cached.Summary)
+		resp.PostmanUpdate = models.PostmanUpdate{
+			Status:    "cached",
+			UpdatedAt: time.Now().Format(time.RFC3339),
+		}
+		return &resp, true
+	}
+
+	return nil, false
+}
+
+// retryQueued waits for the Claude breaker to leave the open state, then re-runs every queued
+// analysis. Analyses that hit an open breaker again are re-queued by AnalyzePR itself.
+// It returns once the queue is empty or the fallback is stopped.
+func (s *AnalyzerService) retryQueued() {
+	defer s.background.Done()
+
+	f := s.fallback
+	ticker := time.NewTicker(breakerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-f.wake:
+		case <-f.stop:
+			return
+		}
+
+		// Consulting the state moves an expired open breaker to half-open
+		if f.breaker != nil && f.breaker.State() == "open" {
+			continue
+		}
+
+		f.mu.Lock()
+		batch := f.queued
+		f.queued = make(map[string]models.GitHubPRPayload)
+		f.mu.Unlock()
+
+		payloads := make([]models.GitHubPRPayload, 0, len(batch))
+		for _, payload := range batch {
+			payloads = append(payloads, payload)
+		}
+		s.runBatch(payloads, s.runQueued)
+
+		f.mu.Lock()
+		if len(f.queued) == 0 {
+			f.running = false
+			f.mu.Unlock()
+			return
+		}
+		f.mu.Unlock()
+	}
+}
+
+// dropQueued stops retrying queued analyses and returns the payloads still waiting
+func (f *breakerFallback) dropQueued() []models.GitHubPRPayload {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stopped {
+		return nil
+	}
+	f.stopped = true
+	close(f.stop)
+
+	dropped := make([]models.GitHubPRPayload, 0, len(f.queued))
+	for _, payload := range f.queued {
+		dropped = append(dropped, payload)
+	}
+	f.queued = make(map[string]models.GitHubPRPayload)
+	return dropped
+}
+
+func (s *AnalyzerService) runQueued(payload models.GitHubPRPayload) {
+	defer s.recoverDeferred("Queued PR analysis", payload)
+
+	runCtx := context.WithValue(context.Background(), skipDebounceKey{}, true)
+	if s.config.AnalysisTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, s.config.AnalysisTimeout)
+		defer cancel()
+	}
+
+	s.logger.Info("Retrying queued PR analysis",
+		"pr_number", payload.PullRequest.Number,
+		"repo", payload.Repository.FullName,
+		"stored_diff_bytes", len(payload.Diff),
+	)
+	if _, err := s.AnalyzePR(runCtx, payload); err != nil {
+		s.logger.Error("Queued PR analysis failed", err,
+			"pr_number", payload.PullRequest.Number,
+			"repo", payload.Repository.FullName,
+		)
+	}
+}
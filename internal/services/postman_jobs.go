@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// postmanJobStore keeps background Postman updates in memory for the job status endpoint.
+// Finished jobs are kept for retention, at most maxRecords of them (0 = unlimited).
+type postmanJobStore struct {
+	mu         sync.Mutex
+	jobs       map[string]*models.PostmanJob
+	retention  time.Duration
+	maxRecords int
+	metrics    interfaces.MetricsCollector
+}
+
+func newPostmanJobStore(retention time.Duration, maxRecords int, metrics interfaces.MetricsCollector) *postmanJobStore {
+	return &postmanJobStore{
+		jobs:       make(map[string]*models.PostmanJob),
+		retention:  retention,
+		maxRecords: maxRecords,
+		metrics:    metrics,
+	}
+}
+
+func (st *postmanJobStore) create(payload models.GitHubPRPayload) *models.PostmanJob {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	job := &models.PostmanJob{
+		ID:         hex.EncodeToString(id),
+		Repository: payload.Repository.FullName,
+		PRNumber:   payload.PullRequest.Number,
+		Status:     models.PostmanStatusPending,
+		CreatedAt:  time.Now(),
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.prune(job.CreatedAt)
+	st.jobs[job.ID] = job
+	return job
+}
+
+func (st *postmanJobStore) complete(id string, update models.PostmanUpdate) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	job, ok := st.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	job.Status = update.Status
+	job.Update = update
+	job.CompletedAt = &now
+}
+
+func (st *postmanJobStore) get(id string) (*models.PostmanJob, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	job, ok := st.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *job
+	return &snapshot, true
+}
+
+// prune drops finished jobs past their retention, then the oldest finished jobs beyond
+// maxRecords. Pending jobs are never dropped. Callers must hold st.mu.
+func (st *postmanJobStore) prune(now time.Time) {
+	var finished []*models.PostmanJob
+	expired := 0
+	for id, job := range st.jobs {
+		if job.CompletedAt == nil {
+			continue
+		}
+		if now.Sub(*job.CompletedAt) > st.retention {
+			delete(st.jobs, id)
+			expired++
+			continue
+		}
+		finished = append(finished, job)
+	}
+
+	excess := 0
+	if st.maxRecords > 0 && len(st.jobs) > st.maxRecords {
+		sort.Slice(finished, func(i, j int) bool { return finished[i].CompletedAt.Before(*finished[j].CompletedAt) })
+		for _, job := range finished {
+			if len(st.jobs) <= st.maxRecords {
+				break
+			}
+			delete(st.jobs, job.ID)
+			excess++
+		}
+	}
+
+	if expired > 0 {
+		st.metrics.AddCounter("postman_jobs_pruned_total", float64(expired), map[string]string{"reason": "age"})
+	}
+	if excess > 0 {
+		st.metrics.AddCounter("postman_jobs_pruned_total", float64(excess), map[string]string{"reason": "count"})
+	}
+}
+
+// cleanup prunes the store every interval, so retention holds even when no new jobs arrive
+func (st *postmanJobStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		st.mu.Lock()
+		st.prune(now)
+		st.mu.Unlock()
+	}
+}
+
+// PostmanJob returns the state of a background Postman update
+func (s *AnalyzerService) PostmanJob(id string) (*models.PostmanJob, bool) {
+	return s.postmanJobs.get(id)
+}
+
+// WaitBackground drops analyses deferred by the debouncer or the breaker fallback, logging each
+// dropped PR, then blocks until background work finishes or ctx is done
+func (s *AnalyzerService) WaitBackground(ctx context.Context) error {
+	s.dropDeferred()
+
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dispatchPostmanUpdate runs the Postman update in the background and returns a pending status
+// referencing the job. Notifiers receive the analysis once the update completes.
+func (s *AnalyzerService) dispatchPostmanUpdate(payload models.GitHubPRPayload, analysisResp *models.AnalysisResponse) models.PostmanUpdate {
+	job := s.postmanJobs.create(payload)
+	// Work on a copy: the caller keeps the pending response
+	result := *analysisResp
+
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+
+		// Detach from the request context, which is cancelled once the response is written
+		updateCtx := context.Background()
+		if s.config.AnalysisTimeout > 0 {
+			var cancel context.CancelFunc
+			updateCtx, cancel = context.WithTimeout(updateCtx, s.config.AnalysisTimeout)
+			defer cancel()
+		}
+
+		result.PostmanUpdate = s.updatePostman(updateCtx, payload, &result)
+		result.PostmanUpdate.JobID = job.ID
+		s.postmanJobs.complete(job.ID, result.PostmanUpdate)
+
+		s.logger.Info("Background Postman update finished",
+			"job_id", job.ID,
+			"pr_number", payload.PullRequest.Number,
+			"postman_status", result.PostmanUpdate.Status,
+		)
+		s.notify(updateCtx, &result)
+	}()
+
+	s.logger.Info("Dispatched Postman update to the background", "job_id", job.ID, "pr_number", payload.PullRequest.Number)
+	return models.PostmanUpdate{
+		Status:    models.PostmanStatusPending,
+		JobID:     job.ID,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+}
+
+// updatePostman applies the analysis to the collection. Failures are reported in the returned
+// status rather than failing the whole analysis.
+func (s *AnalyzerService) updatePostman(ctx context.Context, payload models.GitHubPRPayload, analysisResp *models.AnalysisResponse) (update models.PostmanUpdate) {
+	defer func() {
+		s.emitEvent(models.EventPostmanUpdated, payload, map[string]any{
+			"collection_id":  update.CollectionID,
+			"status":         update.Status,
+			"items_added":    update.ItemsAdded,
+			"items_modified": update.ItemsModified,
+			"items_deleted":  update.ItemsDeleted,
+			"error_code":     update.ErrorCode,
+		})
+	}()
+
+	postmanUpdate, err := s.postmanClient.UpdateCollection(ctx, payload.CollectionID, analysisResp)
+	if err == nil {
+		return *postmanUpdate
+	}
+
+	s.logger.Error("Failed to update Postman collection", err, "pr_number", payload.PullRequest.Number)
+	update = models.PostmanUpdate{
+		Status:       "error",
+		ErrorMessage: err.Error(),
+		ErrorType:    string(pkgerrors.ErrorTypeInternal),
+		UpdatedAt:    time.Now().Format(time.RFC3339),
+	}
+	var appErr *pkgerrors.AppError
+	if errors.As(err, &appErr) {
+		update.ErrorType = string(appErr.Type)
+		update.ErrorCode = appErr.Code
+		if appErr.Code == pkgerrors.CodePostmanBusy {
+			update.Status = models.PostmanStatusBusy
+		}
+	}
+	return update
+}
+
+func (s *AnalyzerService) notify(ctx context.Context, resp *models.AnalysisResponse) {
+	for _, n := range s.notifiers {
+		n.Notify(ctx, resp)
+	}
+}
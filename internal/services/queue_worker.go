@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/validator"
+)
+
+// QueueWorker consumes GitHub PR events from a queue and runs them through the analyzer
+type QueueWorker struct {
+	analyzer  interfaces.AnalyzerService
+	consumer  interfaces.QueueConsumer
+	publisher interfaces.QueuePublisher
+	logger    interfaces.Logger
+	metrics   interfaces.MetricsCollector
+}
+
+// QueueResult is published to the output queue after each analysis
+type QueueResult struct {
+	Status     string                   `json:"status"`
+	Repository string                   `json:"repository"`
+	PRNumber   int                      `json:"pr_number"`
+	Analysis   *models.AnalysisResponse `json:"analysis,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+	Timestamp  string                   `json:"timestamp"`
+}
+
+// NewQueueWorker creates a new queue worker. publisher may be nil when results aren't published.
+func NewQueueWorker(analyzer interfaces.AnalyzerService, consumer interfaces.QueueConsumer, publisher interfaces.QueuePublisher, logger interfaces.Logger, metrics interfaces.MetricsCollector) *QueueWorker {
+	return &QueueWorker{
+		analyzer:  analyzer,
+		consumer:  consumer,
+		publisher: publisher,
+		logger:    logger,
+		metrics:   metrics,
+	}
+}
+
+// Run consumes events until ctx is cancelled
+func (w *QueueWorker) Run(ctx context.Context) error {
+	return w.consumer.Consume(ctx, w.handleMessage)
+}
+
+func (w *QueueWorker) handleMessage(ctx context.Context, data []byte) error {
+	var payload models.GitHubPRPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		w.metrics.IncrementCounter("queue_messages_total", map[string]string{"status": "invalid"})
+		return fmt.Errorf("failed to decode queued GitHub payload: %w", err)
+	}
+
+	if err := validator.Struct(payload); err != nil {
+		w.metrics.IncrementCounter("queue_messages_total", map[string]string{"status": "invalid"})
+		return fmt.Errorf("invalid queued GitHub payload: %w", err)
+	}
+
+	w.logger.Info("Received queued GitHub PR event",
+		"pr_number", payload.PullRequest.Number,
+		"repo", payload.Repository.FullName,
+		"action", payload.Action,
+	)
+
+	result := QueueResult{
+		Status:     "success",
+		Repository: payload.Repository.FullName,
+		PRNumber:   payload.PullRequest.Number,
+	}
+
+	payload.Source = models.PayloadSourceQueue
+	analysis, err := w.analyzer.AnalyzePR(ctx, payload)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	} else {
+		result.Analysis = analysis
+		if analysis.PostmanUpdate.Failed() {
+			result.Status = "partial"
+		}
+	}
+	result.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	w.metrics.IncrementCounter("queue_messages_total", map[string]string{"status": result.Status})
+	w.publish(ctx, result)
+
+	return err
+}
+
+func (w *QueueWorker) publish(ctx context.Context, result QueueResult) {
+	if w.publisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		w.logger.Error("Failed to marshal queue result", err, "pr_number", result.PRNumber)
+		return
+	}
+
+	if err := w.publisher.Publish(ctx, data); err != nil {
+		w.logger.Error("Failed to publish queue result", err, "pr_number", result.PRNumber)
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/secrets"
+)
+
+// redactSecrets replaces credentials found in the diff, failing the analysis instead when FailOnSecrets is set
+func (s *AnalyzerService) redactSecrets(payload models.GitHubPRPayload, diff string) (string, error) {
+	redacted, found := secrets.Redact(diff)
+	total := secrets.Total(found)
+	if total == 0 {
+		return diff, nil
+	}
+
+	for kind, count := range found {
+		s.metrics.AddCounter("diff_secrets_detected_total", float64(count), map[string]string{"kind": kind})
+	}
+	s.logger.Warn("Secrets detected in PR diff",
+		"pr_number", payload.PullRequest.Number,
+		"repo", payload.Repository.FullName,
+		"secrets", total,
+		"fail_on_secrets", s.config.FailOnSecrets,
+	)
+
+	if s.config.FailOnSecrets {
+		return "", pkgerrors.NewValidationError(fmt.Sprintf("diff contains %d potential secret(s)", total)).
+			WithCode(pkgerrors.CodeSecretsDetected).
+			WithContext("secrets", found)
+	}
+	return redacted, nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// maxSummaryPaths caps the changed paths listed in augmented summaries
+const maxSummaryPaths = 20
+
+// buildAugmentedSummary appends facts computed from the analysis itself to Claude's prose:
+// exact route counts, the changed paths and a qualifier for the overall confidence. The format
+// is the same for every analysis, so consumers don't depend on the model's phrasing.
+func buildAugmentedSummary(resp *models.AnalysisResponse) string {
+	var b strings.Builder
+	if prose := strings.TrimSpace(resp.Summary); prose != "" {
+		b.WriteString(prose)
+		b.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(&b, "Changes: %d new, %d modified, %d deleted",
+		len(resp.NewRoutes), len(resp.ModifiedRoutes), len(resp.DeletedRoutes))
+	if n := len(resp.FlaggedRoutes); n > 0 {
+		fmt.Fprintf(&b, " (%d held back for review)", n)
+	}
+	b.WriteString(".\n")
+
+	listed := 0
+	for _, group := range []struct {
+		label  string
+		routes []models.APIRoute
+	}{
+		{"NEW", resp.NewRoutes},
+		{"MODIFIED", resp.ModifiedRoutes},
+		{"DELETED", resp.DeletedRoutes},
+	} {
+		for _, route := range group.routes {
+			if listed == maxSummaryPaths {
+				break
+			}
+			fmt.Fprintf(&b, "- %s %s %s\n", group.label, strings.ToUpper(route.Method), route.Path)
+			listed++
+		}
+	}
+	if remaining := resp.RouteCount() - listed; remaining > 0 {
+		fmt.Fprintf(&b, "- and %d more\n", remaining)
+	}
+
+	fmt.Fprintf(&b, "Confidence: %s (%.2f).", confidenceQualifier(resp.Confidence), resp.Confidence)
+	return b.String()
+}
+
+// confidenceQualifier describes a 0-1 confidence score in words
+func confidenceQualifier(confidence float64) string {
+	switch {
+	case confidence >= 0.85:
+		return "high"
+	case confidence >= 0.6:
+		return "medium"
+	default:
+		return "low"
+	}
+}
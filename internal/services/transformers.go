@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/pathutil"
+)
+
+// TransformerChain applies registered response transformers in registration order
+type TransformerChain struct {
+	transformers []interfaces.ResponseTransformer
+}
+
+// NewTransformerChain creates a transformer chain with the given transformers
+func NewTransformerChain(transformers ...interfaces.ResponseTransformer) *TransformerChain {
+	return &TransformerChain{transformers: transformers}
+}
+
+// Register appends a transformer to the end of the chain
+func (c *TransformerChain) Register(t interfaces.ResponseTransformer) {
+	c.transformers = append(c.transformers, t)
+}
+
+// Len returns the number of registered transformers
+func (c *TransformerChain) Len() int {
+	return len(c.transformers)
+}
+
+// Transform runs every transformer, feeding each one the output of the previous
+func (c *TransformerChain) Transform(ctx context.Context, resp *models.AnalysisResponse) (*models.AnalysisResponse, error) {
+	for i, t := range c.transformers {
+		out, err := t.Transform(ctx, resp)
+		if err != nil {
+			return nil, fmt.Errorf("transformer %d (%T) failed: %w", i, t, err)
+		}
+		if out != nil {
+			resp = out
+		}
+	}
+	return resp, nil
+}
+
+// PathNormalizationTransformer cleans up route paths returned by Claude
+type PathNormalizationTransformer struct{}
+
+// NewPathNormalizationTransformer creates a new path normalization transformer
+func NewPathNormalizationTransformer() *PathNormalizationTransformer {
+	return &PathNormalizationTransformer{}
+}
+
+// Transform normalizes methods and paths on every route in the response
+func (t *PathNormalizationTransformer) Transform(ctx context.Context, resp *models.AnalysisResponse) (*models.AnalysisResponse, error) {
+	for _, routes := range [][]models.APIRoute{resp.NewRoutes, resp.ModifiedRoutes, resp.DeletedRoutes} {
+		for i := range routes {
+			routes[i].Method = strings.ToUpper(strings.TrimSpace(routes[i].Method))
+			routes[i].Path = pathutil.Normalize(routes[i].Path)
+		}
+	}
+	return resp, nil
+}
+
+// PathPrefixTransformer ensures route paths carry a required prefix, e.g. /api/v1
+type PathPrefixTransformer struct {
+	prefix     string
+	exceptions []*regexp.Regexp
+}
+
+// NewPathPrefixTransformer creates a transformer adding prefix to paths that lack it.
+// Paths matching any of the exception regexes are left untouched.
+func NewPathPrefixTransformer(prefix string, exceptions []string) (*PathPrefixTransformer, error) {
+	t := &PathPrefixTransformer{prefix: pathutil.Normalize(prefix)}
+	for _, pattern := range exceptions {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path prefix exception %q: %w", pattern, err)
+		}
+		t.exceptions = append(t.exceptions, re)
+	}
+	return t, nil
+}
+
+// Transform prefixes every route path that doesn't already start with the required prefix
+func (t *PathPrefixTransformer) Transform(ctx context.Context, resp *models.AnalysisResponse) (*models.AnalysisResponse, error) {
+	for _, routes := range [][]models.APIRoute{resp.NewRoutes, resp.ModifiedRoutes, resp.DeletedRoutes} {
+		for i := range routes {
+			routes[i].Path = t.apply(routes[i].Path)
+		}
+	}
+	return resp, nil
+}
+
+func (t *PathPrefixTransformer) apply(path string) string {
+	if t.prefix == "" || t.prefix == "/" || path == t.prefix || strings.HasPrefix(path, t.prefix+"/") {
+		return path
+	}
+	for _, re := range t.exceptions {
+		if re.MatchString(path) {
+			return path
+		}
+	}
+	if path == "" || path == "/" {
+		return t.prefix
+	}
+	return t.prefix + "/" + strings.TrimPrefix(path, "/")
+}
+
+// PathFilterTransformer drops routes whose paths aren't meant to be documented: with allow
+// patterns set a path must match one of them, and it must match none of the deny patterns
+type PathFilterTransformer struct {
+	allow  []*regexp.Regexp
+	deny   []*regexp.Regexp
+	logger interfaces.Logger
+}
+
+// NewPathFilterTransformer creates a transformer filtering routes by allow and deny path regexes
+func NewPathFilterTransformer(allow, deny []string, logger interfaces.Logger) (*PathFilterTransformer, error) {
+	t := &PathFilterTransformer{logger: logger}
+	var err error
+	if t.allow, err = compilePathPatterns(allow); err != nil {
+		return nil, fmt.Errorf("invalid path allow pattern: %w", err)
+	}
+	if t.deny, err = compilePathPatterns(deny); err != nil {
+		return nil, fmt.Errorf("invalid path deny pattern: %w", err)
+	}
+	return t, nil
+}
+
+func compilePathPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Transform removes filtered routes from every route list in the response
+func (t *PathFilterTransformer) Transform(ctx context.Context, resp *models.AnalysisResponse) (*models.AnalysisResponse, error) {
+	resp.NewRoutes = t.filter(resp.NewRoutes, "new")
+	resp.ModifiedRoutes = t.filter(resp.ModifiedRoutes, "modified")
+	resp.DeletedRoutes = t.filter(resp.DeletedRoutes, "deleted")
+	return resp, nil
+}
+
+func (t *PathFilterTransformer) filter(routes []models.APIRoute, change string) []models.APIRoute {
+	kept := make([]models.APIRoute, 0, len(routes))
+	for _, route := range routes {
+		if reason := t.rejects(route.Path); reason != "" {
+			t.logger.Info("Filtered route from documentation",
+				"method", route.Method,
+				"path", route.Path,
+				"change", change,
+				"reason", reason,
+			)
+			continue
+		}
+		kept = append(kept, route)
+	}
+	return kept
+}
+
+// rejects returns why path is filtered out, or "" when it is documented
+func (t *PathFilterTransformer) rejects(path string) string {
+	if len(t.allow) > 0 && !matchesAny(t.allow, path) {
+		return "not_allowed"
+	}
+	if matchesAny(t.deny, path) {
+		return "denied"
+	}
+	return ""
+}
+
+func matchesAny(patterns []*regexp.Regexp, path string) bool {
+	for _, re := range patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/pathutil"
+)
+
+// annotateVersions tags routes with the API version found in their path and links new routes to
+// existing routes they supersede. Detected version bumps are appended to the summary.
+func (s *AnalyzerService) annotateVersions(resp *models.AnalysisResponse, existing []models.ExistingRoute) {
+	for _, routes := range [][]models.APIRoute{resp.NewRoutes, resp.ModifiedRoutes, resp.DeletedRoutes} {
+		for i := range routes {
+			routes[i].Version = pathutil.Version(routes[i].Path)
+		}
+	}
+
+	// Index existing versioned routes by method and version-less path
+	existingVersions := make(map[string][]string)
+	for _, route := range existing {
+		version := pathutil.Version(route.Path)
+		if version == "" {
+			continue
+		}
+		key := strings.ToUpper(route.Method) + " " + pathutil.StripVersion(route.Path)
+		existingVersions[key] = append(existingVersions[key], version)
+	}
+
+	bumps := make(map[string]int)
+	for i := range resp.NewRoutes {
+		route := &resp.NewRoutes[i]
+		if route.Version == "" {
+			continue
+		}
+
+		key := strings.ToUpper(route.Method) + " " + pathutil.StripVersion(route.Path)
+		previous := latestOtherVersion(existingVersions[key], route.Version)
+		if previous == "" {
+			continue
+		}
+
+		route.PrevVersion = previous
+		note := fmt.Sprintf("Supersedes %s of this endpoint.", previous)
+		if route.Description == "" {
+			route.Description = note
+		} else {
+			route.Description += "\n\n" + note
+		}
+		bumps[previous+" -> "+route.Version]++
+	}
+
+	if len(bumps) == 0 {
+		return
+	}
+
+	transitions := make([]string, 0, len(bumps))
+	for transition, count := range bumps {
+		transitions = append(transitions, fmt.Sprintf("%s (%d routes)", transition, count))
+	}
+	sort.Strings(transitions)
+
+	s.logger.Info("API version bump detected", "transitions", transitions)
+	resp.Summary = strings.TrimSpace(resp.Summary + "\n\nAPI version bump detected: " + strings.Join(transitions, ", "))
+}
+
+// latestOtherVersion returns the highest version in versions that differs from current
+func latestOtherVersion(versions []string, current string) string {
+	latest := ""
+	for _, v := range versions {
+		if v == current {
+			continue
+		}
+		if latest == "" || compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// compareVersions compares version segments like v1, v2.1 numerically
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			fmt.Sscanf(pa[i], "%d", &na)
+		}
+		if i < len(pb) {
+			fmt.Sscanf(pb[i], "%d", &nb)
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
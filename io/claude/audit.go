@@ -0,0 +1,31 @@
+package claude
+
+import (
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// SetAuditLogger records every use of a Claude API key to audit
+func (c *Client) SetAuditLogger(audit interfaces.AuditLogger) {
+	c.audit = audit
+}
+
+// auditKeyUse records one Messages API call, identifying the key by its label only
+func (c *Client) auditKeyUse(key *apiKey, repository string, err error) {
+	if c.audit == nil {
+		return
+	}
+	outcome := models.AuditOutcomeSuccess
+	details := map[string]any{"repository": repository}
+	if err != nil {
+		outcome = models.AuditOutcomeFailure
+		details["error"] = err.Error()
+	}
+	c.audit.Record(models.AuditEvent{
+		Action:   models.AuditActionCredentialUse,
+		Actor:    "pr-documentator",
+		Resource: "claude:" + key.label,
+		Outcome:  outcome,
+		Details:  details,
+	})
+}
@@ -0,0 +1,24 @@
+package claude
+
+import (
+	"github.com/igorsal/pr-documentator/pkg/bodylog"
+)
+
+// SetBodyLogging logs the redacted request and response bodies of failed Messages API calls,
+// each truncated to maxBytes; 0 disables it
+func (c *Client) SetBodyLogging(maxBytes int) {
+	c.logBodyBytes = maxBytes
+}
+
+// logFailedCall logs the bodies of a failed call when body logging is enabled. status is 0 when
+// no response was received.
+func (c *Client) logFailedCall(err error, status int, reqBody, respBody []byte) {
+	if c.logBodyBytes <= 0 {
+		return
+	}
+	c.logger.Error("Claude API call failed", err,
+		"status_code", status,
+		"request_body", bodylog.Format(reqBody, c.logBodyBytes),
+		"response_body", bodylog.Format(respBody, c.logBodyBytes),
+	)
+}
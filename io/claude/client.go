@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sony/gobreaker"
@@ -16,6 +19,8 @@ import (
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/models"
 	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/httpclient"
+	"github.com/igorsal/pr-documentator/pkg/limiter"
 )
 
 const (
@@ -30,6 +35,8 @@ const (
 	CircuitBreakerTimeout       = 60 * time.Second
 	ConsecutiveFailureThreshold = 3
 	ShortHashLength             = 7
+	NoToolUseErrorCode          = pkgerrors.CodeClaudeNoToolUse
+	MaxLoggedTextLength         = 2000
 )
 
 type Client struct {
@@ -38,17 +45,38 @@ type Client struct {
 	logger         interfaces.Logger
 	circuitBreaker interfaces.CircuitBreaker
 	metrics        interfaces.MetricsCollector
+	promptFilter   *promptFilter
+	keys           atomic.Pointer[keyPool]
+
+	costMu     sync.Mutex
+	costTotals map[string]float64
+
+	rateLimits rateLimitListeners
+	audit      interfaces.AuditLogger
+	chunks     *limiter.Adaptive // shared by the chunk summarizations of all analyses
+
+	logBodyBytes int // set by SetBodyLogging
 }
 
 // NewClient creates a new Claude API client with circuit breaker and metrics
 func NewClient(cfg config.ClaudeConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *Client {
 	// Configure HTTP client
-	client := &http.Client{
-		Timeout: cfg.Timeout,
+	if cfg.TLS.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is DISABLED for Claude API calls, do not use in production", "service", "claude")
+	}
+	client, err := httpclient.New(httpclient.Options{
+		Timeout:            cfg.Timeout,
+		CABundleFile:       cfg.TLS.CABundleFile,
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+	})
+	if err != nil {
+		logger.Error("Failed to configure custom TLS for Claude API, using default HTTP client", err)
+		client = &http.Client{Timeout: cfg.Timeout}
 	}
 
 	// Configure circuit breaker
-	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+	cbWrapper := &circuitBreakerWrapper{}
+	cbWrapper.cb = gobreaker.NewCircuitBreaker(gobreaker.Settings{
 		Name:        CircuitBreakerName,
 		MaxRequests: MaxCircuitBreakerRequests,
 		Interval:    CircuitBreakerInterval,
@@ -62,24 +90,51 @@ func NewClient(cfg config.ClaudeConfig, logger interfaces.Logger, metrics interf
 				"from", from.String(),
 				"to", to.String(),
 			)
+			cbWrapper.notify(from.String(), to.String())
 		},
 	})
 
-	// Wrap circuit breaker in interface
-	cbWrapper := &circuitBreakerWrapper{cb: cb}
-
-	return &Client{
+	c := &Client{
 		httpClient:     client,
 		config:         cfg,
 		logger:         logger,
 		circuitBreaker: cbWrapper,
 		metrics:        metrics,
+		promptFilter:   newPromptFilter(cfg.Prompt),
+		costTotals:     make(map[string]float64),
 	}
+	c.keys.Store(newKeyPool(cfg.Keys(), nil, logger))
+	c.chunks = limiter.New(cfg.ChunkConcurrency, func(limit, inUse int) {
+		metrics.SetGauge("claude_chunk_concurrency", float64(limit), map[string]string{"state": "limit"})
+		metrics.SetGauge("claude_chunk_concurrency", float64(inUse), map[string]string{"state": "in_use"})
+	})
+	c.OnRateLimit(c.chunks.Observe)
+	return c
 }
 
 // circuitBreakerWrapper implements interfaces.CircuitBreaker
 type circuitBreakerWrapper struct {
 	cb *gobreaker.CircuitBreaker
+
+	listenersMu sync.Mutex
+	listeners   []func(from, to string)
+}
+
+// OnStateChange registers fn to be called after every state transition
+func (w *circuitBreakerWrapper) OnStateChange(fn func(from, to string)) {
+	w.listenersMu.Lock()
+	defer w.listenersMu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+func (w *circuitBreakerWrapper) notify(from, to string) {
+	w.listenersMu.Lock()
+	listeners := append([]func(from, to string){}, w.listeners...)
+	w.listenersMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(from, to)
+	}
 }
 
 func (w *circuitBreakerWrapper) Execute(req func() (any, error)) (any, error) {
@@ -94,6 +149,11 @@ func (w *circuitBreakerWrapper) State() string {
 	return w.cb.State().String()
 }
 
+// CircuitBreaker exposes the client's circuit breaker for health reporting
+func (c *Client) CircuitBreaker() interfaces.CircuitBreaker {
+	return c.circuitBreaker
+}
+
 // AnalyzePR analyzes a pull request using Claude with function calling, circuit breaker, and metrics
 func (c *Client) AnalyzePR(ctx context.Context, req models.AnalysisRequest) (*models.AnalysisResponse, error) {
 	startTime := time.Now()
@@ -128,7 +188,7 @@ func (c *Client) AnalyzePR(ctx context.Context, req models.AnalysisRequest) (*mo
 				"pr_number", req.PullRequest.Number,
 				"state", c.circuitBreaker.State(),
 			)
-			return nil, pkgerrors.NewUnavailableError("claude").WithCause(err)
+			return nil, pkgerrors.NewUnavailableError("claude").WithCode(pkgerrors.CodeClaudeCircuitOpen).WithCause(err)
 		}
 
 		c.logger.Error("Failed to analyze PR with Claude", err, "pr_number", req.PullRequest.Number)
@@ -146,6 +206,9 @@ func (c *Client) AnalyzePR(ctx context.Context, req models.AnalysisRequest) (*mo
 		"modified_routes", len(analysisResp.ModifiedRoutes),
 		"deleted_routes", len(analysisResp.DeletedRoutes),
 		"confidence", analysisResp.Confidence,
+		"input_tokens", analysisResp.Usage.InputTokens,
+		"output_tokens", analysisResp.Usage.OutputTokens,
+		"analysis_path", analysisResp.AnalysisPath,
 		"duration_ms", duration*1000,
 	)
 
@@ -154,11 +217,35 @@ func (c *Client) AnalyzePR(ctx context.Context, req models.AnalysisRequest) (*mo
 
 // executeAnalysis performs the actual Claude API call
 func (c *Client) executeAnalysis(ctx context.Context, req models.AnalysisRequest) (*models.AnalysisResponse, error) {
-	prompt := buildAnalysisPrompt(req)
-	analysisToolSchema := buildAnalysisToolSchema()
+	var usage Usage
+	filtered := c.promptFilter.apply(req)
+	prompt := buildAnalysisPrompt(filtered)
+	analysisPath := models.AnalysisPathDirect
+	diffCoverage := 1.0
+
+	// Degrade to analyzing a summary of the diff rather than sending a prompt Claude can't take
+	if c.config.MaxPromptBytes > 0 && len(prompt) > c.config.MaxPromptBytes {
+		fitted, budget, err := c.fitDiffBudget(filtered)
+		if err != nil {
+			return nil, err
+		}
+		filtered = fitted
+		summary, summaryUsage, err := c.summarizeDiff(ctx, filtered, budget)
+		usage.add(summaryUsage)
+		if err != nil {
+			return nil, err
+		}
+		diffCoverage = summary.coverage()
+		filtered.Diff = summary.text
+		prompt = buildAnalysisPrompt(filtered)
+		analysisPath = models.AnalysisPathSummarized
+	}
+
+	analysisToolSchema := buildAnalysisToolSchema(c.config.RequiredFields)
 
+	// Repositories can override the global model, e.g. a cheaper one for simple services
 	claudeReq := ClaudeRequest{
-		Model:     c.config.Model,
+		Model:     c.config.ModelFor(req.Repository.FullName),
 		MaxTokens: c.config.MaxTokens,
 		Messages: []Message{
 			{
@@ -174,29 +261,175 @@ func (c *Client) executeAnalysis(ctx context.Context, req models.AnalysisRequest
 		},
 	}
 
+	toolUse, requestUsage, err := c.sendToolRequest(ctx, claudeReq, "analyze_api_changes", req.Repository.FullName)
+	usage.add(requestUsage)
+	if err != nil && isNoToolUseError(err) {
+		switch c.config.NoToolUseMode {
+		case config.NoToolUseModeEmpty:
+			// Treat a refusal or explanation as "no API changes detected"
+			return &models.AnalysisResponse{
+				NewRoutes:      []models.APIRoute{},
+				ModifiedRoutes: []models.APIRoute{},
+				DeletedRoutes:  []models.APIRoute{},
+				Summary:        "Claude did not return a structured analysis; treating as no API changes",
+				Usage:          usage.toModel(),
+				AnalysisPath:   analysisPath,
+			}, nil
+		case config.NoToolUseModeRetry:
+			c.logger.Info("Retrying Claude analysis with explicit tool instruction", "pr_number", req.PullRequest.Number)
+			claudeReq.Messages = append(claudeReq.Messages,
+				Message{Role: "assistant", Content: "I will analyze the diff."},
+				Message{Role: "user", Content: "You must respond ONLY by calling the analyze_api_changes tool. If there are no API changes, call it with empty route arrays."},
+			)
+			toolUse, requestUsage, err = c.sendToolRequest(ctx, claudeReq, "analyze_api_changes", req.Repository.FullName)
+			usage.add(requestUsage)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the tool input to our analysis response
+	analysisResp, err := c.convertToolInputToAnalysis(toolUse.Input, usage)
+	if err != nil {
+		return nil, pkgerrors.WrapError(err, "failed to convert Claude response to analysis")
+	}
+
+	// Optionally ask for routes the first tool call missed
+	if c.config.MaxContinuationTurns > 0 {
+		analysisResp = c.continueAnalysis(ctx, claudeReq, toolUse, analysisResp, req, usage)
+	}
+	analysisResp.AnalysisPath = analysisPath
+
+	// Routes in diff chunks that couldn't be summarized are unknown, so trust the analysis less
+	if diffCoverage < 1 {
+		c.logger.Warn("Lowering confidence for diff parts left out of the analysis",
+			"pr_number", req.PullRequest.Number,
+			"coverage", diffCoverage,
+			"confidence", analysisResp.Confidence,
+		)
+		analysisResp.Confidence *= diffCoverage
+	}
+
+	return analysisResp, nil
+}
+
+// sendToolRequest sends a forced tool-use request to Claude and returns the matching tool use block
+// together with the tokens the request used, which are also reported when no tool was called
+func (c *Client) sendToolRequest(ctx context.Context, claudeReq ClaudeRequest, toolName, repository string) (*Content, Usage, error) {
 	// Marshal request body
 	body, err := json.Marshal(claudeReq)
 	if err != nil {
-		return nil, pkgerrors.NewExternalError("claude", "failed to marshal request").WithCause(err)
+		return nil, Usage{}, pkgerrors.NewExternalError("claude", "failed to marshal request").WithCause(err)
+	}
+
+	if debug := models.PromptDebugFrom(ctx); debug != nil {
+		debug.Record(body)
+	}
+
+	respBody, err := c.postWithKeyPool(ctx, body, repository)
+	if err != nil {
+		return nil, Usage{}, err
 	}
 
+	// Parse response
+	var claudeResp ClaudeResponse
+	if err := json.Unmarshal(respBody, &claudeResp); err != nil {
+		return nil, Usage{}, pkgerrors.NewExternalError("claude", "failed to parse response").WithCause(err)
+	}
+
+	c.recordUsage(repository, claudeReq.Model, claudeResp.Usage)
+
+	if len(claudeResp.Content) == 0 {
+		return nil, claudeResp.Usage, pkgerrors.NewExternalError("claude", "empty response content")
+	}
+
+	// Find the tool use in the response, keeping any text Claude returned instead for diagnosis
+	var text strings.Builder
+	for _, content := range claudeResp.Content {
+		if content.Type == "tool_use" && content.Name == toolName {
+			return &content, claudeResp.Usage, nil
+		}
+		if content.Type == "text" {
+			text.WriteString(content.Text)
+		}
+	}
+
+	claudeText := text.String()
+	if len(claudeText) > MaxLoggedTextLength {
+		claudeText = claudeText[:MaxLoggedTextLength] + "..."
+	}
+
+	c.logger.Warn("Claude responded without calling the tool",
+		"tool", toolName,
+		"stop_reason", claudeResp.StopReason,
+		"claude_text", claudeText,
+	)
+
+	noToolErr := pkgerrors.NewExternalError("claude", "no tool use found in response").
+		WithContext("stop_reason", claudeResp.StopReason).
+		WithContext("claude_text", claudeText)
+	noToolErr.Code = NoToolUseErrorCode
+	return nil, claudeResp.Usage, noToolErr
+}
+
+// isNoToolUseError reports whether err is the "no tool use found" error from sendToolRequest
+func isNoToolUseError(err error) bool {
+	appErr, ok := pkgerrors.AsAppError(err)
+	return ok && appErr.Code == NoToolUseErrorCode
+}
+
+// postWithKeyPool sends a Messages API request, rotating through the key pool. Keys that are
+// rejected or rate limited are skipped in favour of the next one, each behind its own circuit breaker.
+func (c *Client) postWithKeyPool(ctx context.Context, body []byte, repository string) ([]byte, error) {
+	keys := c.keys.Load().candidates()
+	if len(keys) == 0 {
+		return nil, pkgerrors.NewUnavailableError("claude").WithContext("reason", "all API keys are unavailable")
+	}
+
+	var lastErr error
+	for _, key := range keys {
+		result, err := key.breaker.Execute(func() (any, error) {
+			return c.postMessages(ctx, body, key.value)
+		})
+		if !errors.Is(err, gobreaker.ErrOpenState) && !errors.Is(err, gobreaker.ErrTooManyRequests) {
+			c.auditKeyUse(key, repository, err)
+		}
+		if err == nil {
+			return result.([]byte), nil
+		}
+		lastErr = err
+		if !isKeyError(err) && !errors.Is(err, gobreaker.ErrOpenState) && !errors.Is(err, gobreaker.ErrTooManyRequests) {
+			return nil, err
+		}
+		if len(keys) > 1 {
+			c.logger.Warn("Claude API key unavailable, trying next key", "key", key.label, "error", err)
+		}
+	}
+	return nil, lastErr
+}
+
+// postMessages performs a single Messages API call with the given key and returns the raw response body
+func (c *Client) postMessages(ctx context.Context, body []byte, key string) ([]byte, error) {
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+MessagesEndpoint, bytes.NewBuffer(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+MessagesEndpoint, bytes.NewReader(body))
 	if err != nil {
 		return nil, pkgerrors.NewExternalError("claude", "failed to create request").WithCause(err)
 	}
 
 	// Set headers
 	httpReq.Header.Set("Content-Type", ContentTypeJSON)
-	httpReq.Header.Set(APIKeyHeader, c.config.APIKey)
+	httpReq.Header.Set(APIKeyHeader, key)
 	httpReq.Header.Set(VersionHeader, AnthropicVersion)
 
 	// Execute request
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		c.logFailedCall(err, 0, body, nil)
 		return nil, pkgerrors.NewExternalError("claude", err.Error()).WithCause(err)
 	}
 	defer resp.Body.Close()
+	c.observeRateLimit(resp)
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
@@ -209,48 +442,22 @@ func (c *Client) executeAnalysis(ctx context.Context, req models.AnalysisRequest
 		errorMsg := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody))
 
 		// Handle specific error cases
+		var httpErr *pkgerrors.AppError
 		switch resp.StatusCode {
 		case 401:
-			return nil, pkgerrors.NewUnauthorizedError("Invalid Claude API key")
+			httpErr = pkgerrors.NewUnauthorizedError("Invalid Claude API key").WithCode(pkgerrors.CodeClaudeUnauthorized)
 		case 429:
-			return nil, pkgerrors.NewRateLimitError("claude")
+			httpErr = pkgerrors.NewRateLimitError("claude")
 		case 500, 502, 503, 504:
-			return nil, pkgerrors.NewUnavailableError("claude").WithContext("status_code", resp.StatusCode)
+			httpErr = pkgerrors.NewUnavailableError("claude").WithContext("status_code", resp.StatusCode)
 		default:
-			return nil, pkgerrors.NewExternalError("claude", errorMsg)
-		}
-	}
-
-	// Parse response
-	var claudeResp ClaudeResponse
-	if err := json.Unmarshal(respBody, &claudeResp); err != nil {
-		return nil, pkgerrors.NewExternalError("claude", "failed to parse response").WithCause(err)
-	}
-
-	if len(claudeResp.Content) == 0 {
-		return nil, pkgerrors.NewExternalError("claude", "empty response content")
-	}
-
-	// Find the tool use in the response
-	var toolUse *Content
-	for _, content := range claudeResp.Content {
-		if content.Type == "tool_use" && content.Name == "analyze_api_changes" {
-			toolUse = &content
-			break
+			httpErr = pkgerrors.NewExternalError("claude", errorMsg)
 		}
+		c.logFailedCall(httpErr, resp.StatusCode, body, respBody)
+		return nil, httpErr
 	}
 
-	if toolUse == nil {
-		return nil, pkgerrors.NewExternalError("claude", "no tool use found in response")
-	}
-
-	// Convert the tool input to our analysis response
-	analysisResp, err := c.convertToolInputToAnalysis(toolUse.Input)
-	if err != nil {
-		return nil, pkgerrors.WrapError(err, "failed to convert Claude response to analysis")
-	}
-
-	return analysisResp, nil
+	return respBody, nil
 }
 
 // Remove obsolete function - now using Resty in executeAnalysis
@@ -272,6 +479,26 @@ func buildAnalysisPrompt(req models.AnalysisRequest) string {
 		existingRoutesContext += "- **DELETED**: Route exists in collection but removed from code\n"
 	}
 
+	if len(req.IntendedChanges) > 0 {
+		existingRoutesContext += "\n**Intended API Changes (from PR description):**\n"
+		for _, change := range req.IntendedChanges {
+			existingRoutesContext += fmt.Sprintf("- %s\n", change)
+		}
+		existingRoutesContext += "\nFocus the diff analysis on confirming these changes, but report anything else the diff shows.\n"
+	}
+
+	if len(req.RenamedFiles) > 0 {
+		existingRoutesContext += "\n**Renamed/Moved Files:**\n"
+		for _, rename := range req.RenamedFiles {
+			existingRoutesContext += fmt.Sprintf("- %s -> %s\n", rename.From, rename.To)
+		}
+		existingRoutesContext += "\nRoutes defined in these files moved with them. Do NOT report a route as both deleted and new just because its file moved; only report real changes to the route itself.\n"
+	}
+
+	if len(req.DeclarativeFiles) > 0 {
+		existingRoutesContext += declarativeFilesContext(req.DeclarativeFiles)
+	}
+
 	return fmt.Sprintf(`
 Please analyze the following GitHub Pull Request to identify API changes and provide a structured response.
 
@@ -292,25 +519,56 @@ Please analyze the following GitHub Pull Request to identify API changes and pro
 
 2. **New Routes:** 
    - Only include routes NOT in the existing collection
-   - Include HTTP method, path, description, parameters, request body and response
+   - Include HTTP method, path, description, parameters, request body, response and the auth scheme the route requires
+   - For parameters, include enum values, format (date-time, uuid, ...) and minimum/maximum when the code constrains them
+   - Document every status code the route can return in responses (e.g. 201 on create, 400 on validation errors, 404 when not found), not just the success case
    - Suggest appropriate folder placement based on existing organization
 
 3. **Modified Routes:** 
    - Only include routes that exist in collection but have changes
    - Detail what specifically changed (method, path, parameters, etc.)
+   - Treat added, removed or changed authentication/authorization middleware (auth guards, API key checks, role requirements) on an existing route as a modification even if its signature is unchanged; set auth.changed=true with a note
 
 4. **Deleted Routes:**
    - Include routes from collection that are no longer in the codebase
    - Provide reason for removal/deprecation
+   - Set removed=true when the handler is deleted entirely, deprecated=true when it is still served but discouraged
 
-5. **Postman Documentation:**
+5. **Pagination:**
+   - For list endpoints, set pagination to page (page/limit), offset (offset/limit) or cursor (cursor/limit)
+   - Include the pagination query parameters actually read by the handler in parameters
+
+6. **Source Location:**
+   - For each route, set source_file and source_line to where it is defined, using the diff hunk headers to compute the line
+
+7. **Callbacks / Webhooks:**
+   - Report outbound webhook or callback definitions (events the service POSTs to subscriber URLs) in callbacks, not as routes
+   - Include the event name, payload example, signature headers and the route that registers the subscriber
+
+8. **Postman Documentation:**
    - Ensure each route has clear, detailed descriptions
    - Include request and response examples
    - Use {{baseUrl}} for environment variables
    - Respect existing folder structure
 
-6. **Confidence:** 
+9. **Confidence:** 
    - Provide confidence score (0-1) based on analysis accuracy
+   - Give every route its own confidence score (0-1); be conservative for routes inferred indirectly
+
+10. **Rate Limiting:**
+   - When a route gets rate limiting (rate-limit middleware, throttling decorators, token buckets), set rate_limit with the limit, window and scope the code configures
+   - List in rate_limit.headers the rate-limit response headers the route returns (X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, RateLimit-*, Retry-After on 429)
+   - Document the 429 Too Many Requests response in responses
+
+11. **Breaking Changes:**
+   - List in breaking_changes every change that can break existing clients, one short sentence each naming the route
+   - Breaking: removed routes, changed method or path, removed or renamed parameters and response fields, optional parameters or body fields becoming required, changed types or enum values narrowed, stricter auth
+   - Not breaking: new routes, new optional parameters or response fields, deprecations that keep serving the route
+   - Compare modified and deleted routes against the existing routes above
+
+12. **Event Channels:**
+   - Report message channels the service publishes to or consumes from (Kafka topics, AMQP/SQS queues, NATS subjects, SNS topics, WebSocket events) that the PR adds, changes or removes in channels, not as routes
+   - Include the channel name, whether the service publishes or consumes, the protocol and an example message payload with its headers
 
 **PR Diff to Analyze:**
 %s
@@ -319,8 +577,8 @@ Please analyze the following GitHub Pull Request to identify API changes and pro
 `, req.PullRequest.Title, req.PullRequest.Body, req.Repository.FullName, req.PullRequest.Number, req.PullRequest.DiffURL, existingRoutesContext, req.Diff)
 }
 
-// buildAnalysisToolSchema creates the JSON schema for the analysis tool
-func buildAnalysisToolSchema() Tool {
+// buildAnalysisToolSchema creates the JSON schema for the analysis tool, requiring the given top-level fields
+func buildAnalysisToolSchema(required []string) Tool {
 	return Tool{
 		Name:        "analyze_api_changes",
 		Description: "Analyze GitHub Pull Request diffs to identify API route changes and return structured data about new, modified, or deleted endpoints",
@@ -333,24 +591,19 @@ func buildAnalysisToolSchema() Tool {
 					Items: &Property{
 						Type: "object",
 						Properties: map[string]Property{
-							"method":      {Type: "string", Description: "HTTP method (GET, POST, PUT, DELETE, etc.)"},
-							"path":        {Type: "string", Description: "API endpoint path (e.g., /api/v1/users)"},
-							"description": {Type: "string", Description: "Description of what this endpoint does"},
-							"parameters": {
-								Type: "array",
-								Items: &Property{
-									Type: "object",
-									Properties: map[string]Property{
-										"name":        {Type: "string", Description: "Parameter name"},
-										"in":          {Type: "string", Description: "Parameter location (query, path, header, body)"},
-										"type":        {Type: "string", Description: "Parameter type (string, number, boolean, etc.)"},
-										"required":    {Type: "boolean", Description: "Whether parameter is required"},
-										"description": {Type: "string", Description: "Parameter description"},
-									},
-								},
-							},
+							"method":       {Type: "string", Description: "HTTP method (GET, POST, PUT, DELETE, etc.)"},
+							"path":         {Type: "string", Description: "API endpoint path (e.g., /api/v1/users)"},
+							"description":  {Type: "string", Description: "Description of what this endpoint does"},
+							"parameters":   parametersSchema(),
 							"request_body": {Type: "object", Description: "Request body schema"},
 							"response":     {Type: "object", Description: "Response body schema"},
+							"responses":    responsesSchema(),
+							"confidence":   routeConfidenceSchema(),
+							"pagination":   paginationSchema(),
+							"source_file":  sourceFileSchema(),
+							"source_line":  sourceLineSchema(),
+							"auth":         authSchema(),
+							"rate_limit":   rateLimitSchema(),
 						},
 					},
 				},
@@ -363,8 +616,16 @@ func buildAnalysisToolSchema() Tool {
 							"method":       {Type: "string", Description: "HTTP method"},
 							"path":         {Type: "string", Description: "API endpoint path"},
 							"description":  {Type: "string", Description: "Description of changes made"},
+							"parameters":   parametersSchema(),
 							"request_body": {Type: "object", Description: "Updated request body schema"},
 							"response":     {Type: "object", Description: "Updated response body schema"},
+							"responses":    responsesSchema(),
+							"confidence":   routeConfidenceSchema(),
+							"pagination":   paginationSchema(),
+							"source_file":  sourceFileSchema(),
+							"source_line":  sourceLineSchema(),
+							"auth":         authSchema(),
+							"rate_limit":   rateLimitSchema(),
 						},
 					},
 				},
@@ -374,12 +635,40 @@ func buildAnalysisToolSchema() Tool {
 					Items: &Property{
 						Type: "object",
 						Properties: map[string]Property{
-							"method": {Type: "string", Description: "HTTP method"},
-							"path":   {Type: "string", Description: "API endpoint path"},
-							"reason": {Type: "string", Description: "Reason for deletion/deprecation"},
+							"method":      {Type: "string", Description: "HTTP method"},
+							"path":        {Type: "string", Description: "API endpoint path"},
+							"reason":      {Type: "string", Description: "Reason for deletion/deprecation"},
+							"removed":     {Type: "boolean", Description: "True if the route handler was deleted from the codebase entirely"},
+							"deprecated":  {Type: "boolean", Description: "True if the route still exists but is marked deprecated or discouraged"},
+							"confidence":  routeConfidenceSchema(),
+							"source_file": sourceFileSchema(),
+							"source_line": sourceLineSchema(),
+						},
+					},
+				},
+				"callbacks": {
+					Type:        "array",
+					Description: "Outbound webhooks/callbacks the API sends to subscribers, added or changed in the PR",
+					Items: &Property{
+						Type: "object",
+						Properties: map[string]Property{
+							"name":         {Type: "string", Description: "Event name (e.g. order.created)"},
+							"method":       {Type: "string", Description: "HTTP method used to deliver the callback, usually POST"},
+							"url":          {Type: "string", Description: "Destination, as a runtime expression when it comes from the request (e.g. {$request.body#/callbackUrl})"},
+							"description":  {Type: "string", Description: "When the callback is sent and what it means"},
+							"payload":      {Type: "object", Description: "Example payload sent to the subscriber"},
+							"headers":      {Type: "array", Description: "Headers sent with the callback, e.g. signatures", Items: &Property{Type: "object", Properties: map[string]Property{"name": {Type: "string"}, "description": {Type: "string"}, "example": {Type: "string"}}}},
+							"triggered_by": {Type: "string", Description: "Route that registers the subscriber, e.g. POST /subscriptions"},
 						},
+						Required: []string{"name", "method", "description"},
 					},
 				},
+				"channels": channelsSchema(),
+				"breaking_changes": {
+					Type:        "array",
+					Description: "Changes that can break existing clients (removed routes, removed/renamed fields, newly required parameters, stricter auth), one sentence each naming the route",
+					Items:       &Property{Type: "string"},
+				},
 				"summary": {
 					Type:        "string",
 					Description: "Brief summary of all API changes found in this PR",
@@ -388,14 +677,136 @@ func buildAnalysisToolSchema() Tool {
 					Type:        "number",
 					Description: "Confidence score between 0 and 1 for the analysis accuracy",
 				},
+				"complete": {
+					Type:        "boolean",
+					Description: "False if there are more routes to report in a follow-up call",
+				},
+			},
+			Required: required,
+		},
+	}
+}
+
+// authSchema describes the authentication a route requires and whether the PR changed it
+func authSchema() Property {
+	return Property{
+		Type:        "object",
+		Description: "Authentication required by the route",
+		Properties: map[string]Property{
+			"scheme":      {Type: "string", Description: "Authentication scheme", Enum: []string{"none", "bearer", "api_key", "basic", "oauth2"}},
+			"header_name": {Type: "string", Description: "Header carrying the key for api_key auth (e.g. X-API-Key)"},
+			"changed":     {Type: "boolean", Description: "True if the PR added, removed or changed auth/security middleware on this route"},
+			"note":        {Type: "string", Description: "What changed, e.g. \"now requires a bearer token\" or \"admin role required\""},
+		},
+		Required: []string{"scheme"},
+	}
+}
+
+// channelsSchema describes message channels of event-driven APIs
+func channelsSchema() Property {
+	return Property{
+		Type:        "array",
+		Description: "Message channels (topics, queues, subjects) the service publishes to or consumes from, added, changed or removed in the PR",
+		Items: &Property{
+			Type: "object",
+			Properties: map[string]Property{
+				"name":        {Type: "string", Description: "Channel address, e.g. orders.created"},
+				"action":      {Type: "string", Description: "Whether the service publishes to or consumes from the channel", Enum: []string{models.ChannelActionPublish, models.ChannelActionConsume}},
+				"change":      {Type: "string", Description: "How the PR changes the channel", Enum: []string{models.ChannelChangeNew, models.ChannelChangeModified, models.ChannelChangeDeleted}},
+				"protocol":    {Type: "string", Description: "Messaging protocol, e.g. kafka, amqp, nats, sqs, sns, mqtt, ws"},
+				"description": {Type: "string", Description: "What the messages mean and when they are sent"},
+				"message": {
+					Type:        "object",
+					Description: "Message carried on the channel",
+					Properties: map[string]Property{
+						"name":         {Type: "string", Description: "Message name, e.g. OrderCreated"},
+						"content_type": {Type: "string", Description: "Payload content type, e.g. application/json"},
+						"payload":      {Type: "object", Description: "Example message payload"},
+						"headers":      {Type: "array", Description: "Message headers", Items: &Property{Type: "object", Properties: map[string]Property{"name": {Type: "string"}, "description": {Type: "string"}, "example": {Type: "string"}}}},
+					},
+				},
 			},
-			Required: []string{"new_routes", "modified_routes", "deleted_routes", "summary", "confidence"},
+			Required: []string{"name", "action", "change"},
 		},
 	}
 }
 
-// convertToolInputToAnalysis converts Claude's tool input to our AnalysisResponse
-func (c *Client) convertToolInputToAnalysis(input map[string]any) (*models.AnalysisResponse, error) {
+// rateLimitSchema describes rate limiting applied to a route
+func rateLimitSchema() Property {
+	return Property{
+		Type:        "object",
+		Description: "Rate limiting applied to the route; omit when the route isn't rate limited",
+		Properties: map[string]Property{
+			"limit":   {Type: "integer", Description: "Requests allowed per window"},
+			"window":  {Type: "string", Description: "Window length as a duration, e.g. 1s, 1m, 1h"},
+			"scope":   {Type: "string", Description: "What the limit is counted per, e.g. ip, user, api_key, global"},
+			"headers": {Type: "array", Description: "Rate-limit response headers returned, e.g. X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After", Items: &Property{Type: "string"}},
+		},
+	}
+}
+
+// responsesSchema describes example responses keyed by HTTP status code
+func responsesSchema() Property {
+	return Property{
+		Type:        "object",
+		Description: "Example responses keyed by HTTP status code (e.g. \"201\", \"400\", \"404\"), covering the success response and every error response the handler can return",
+		AdditionalProperties: &Property{
+			Type: "object",
+			Properties: map[string]Property{
+				"description": {Type: "string", Description: "When this status code is returned"},
+				"body":        {Type: "object", Description: "Example response body"},
+			},
+		},
+	}
+}
+
+// sourceFileSchema and sourceLineSchema locate a route's definition in the diff
+func sourceFileSchema() Property {
+	return Property{Type: "string", Description: "Path of the file in the diff where the route is defined (e.g. src/routes/users.js)"}
+}
+
+func sourceLineSchema() Property {
+	return Property{Type: "integer", Description: "Line number of the route definition in the new version of source_file, from the diff hunk headers"}
+}
+
+// paginationSchema describes the pagination style of list endpoints
+func paginationSchema() Property {
+	return Property{
+		Type:        "string",
+		Description: "Pagination style for list endpoints; omit for endpoints that don't paginate",
+		Enum:        []string{models.PaginationPage, models.PaginationOffset, models.PaginationCursor},
+	}
+}
+
+// routeConfidenceSchema describes the per-route confidence score
+func routeConfidenceSchema() Property {
+	return Property{Type: "number", Description: "Confidence score between 0 and 1 that this route change is real and correctly described"}
+}
+
+// parametersSchema describes route parameters, including enum, format and range constraints
+func parametersSchema() Property {
+	return Property{
+		Type: "array",
+		Items: &Property{
+			Type: "object",
+			Properties: map[string]Property{
+				"name":        {Type: "string", Description: "Parameter name"},
+				"in":          {Type: "string", Description: "Parameter location (query, path, header, body)"},
+				"type":        {Type: "string", Description: "Parameter type (string, number, boolean, etc.)"},
+				"required":    {Type: "boolean", Description: "Whether parameter is required"},
+				"description": {Type: "string", Description: "Parameter description"},
+				"enum":        {Type: "array", Description: "Allowed values, if restricted", Items: &Property{Type: "string"}},
+				"format":      {Type: "string", Description: "Value format (date-time, uuid, email, uri, etc.)"},
+				"minimum":     {Type: "number", Description: "Minimum numeric value or string length"},
+				"maximum":     {Type: "number", Description: "Maximum numeric value or string length"},
+			},
+		},
+	}
+}
+
+// convertToolInputToAnalysis converts Claude's tool input to our AnalysisResponse, attaching the
+// tokens spent to produce it
+func (c *Client) convertToolInputToAnalysis(input map[string]any, usage Usage) (*models.AnalysisResponse, error) {
 	// Marshal and unmarshal to convert to our struct
 	jsonData, err := json.Marshal(input)
 	if err != nil {
@@ -406,6 +817,19 @@ func (c *Client) convertToolInputToAnalysis(input map[string]any) (*models.Analy
 	if err := json.Unmarshal(jsonData, &analysisResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal to AnalysisResponse: %w", err)
 	}
+	analysisResp.Usage = usage.toModel()
+
+	// Route lists that aren't required may be omitted or null when there's nothing to report
+	for _, routes := range []*[]models.APIRoute{&analysisResp.NewRoutes, &analysisResp.ModifiedRoutes, &analysisResp.DeletedRoutes} {
+		if *routes == nil {
+			*routes = []models.APIRoute{}
+		}
+	}
+	for _, field := range c.config.RequiredFields {
+		if _, ok := input[field]; !ok {
+			c.logger.Warn("Claude omitted a required analysis field", "field", field)
+		}
+	}
 
 	return &analysisResp, nil
 }
@@ -423,6 +847,7 @@ You must use the analyze_api_changes tool to return structured data. Be thorough
 
 Guidelines:
 - Look for HTTP route definitions (app.get, router.post, @RequestMapping, etc.)
+- Routes can also be declared in OpenAPI specs, Terraform, serverless.yml or CloudFormation/SAM templates; treat changes there like code changes
 - Identify request/response payload structures
 - Note parameter changes (query params, path params, headers)
 - Detect middleware changes that affect API behavior
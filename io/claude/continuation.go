@@ -0,0 +1,98 @@
+package claude
+
+import (
+	"context"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const continuationPrompt = `Are there more API routes in this diff that you have not reported yet?
+If so, call analyze_api_changes again with ONLY the additional routes and set complete to true once nothing is left.
+If everything has been reported, call analyze_api_changes with empty route arrays and complete set to true.`
+
+// continueAnalysis asks Claude for routes missed by the first tool call, accumulating results
+// until Claude reports completion, returns nothing new, or MaxContinuationTurns is reached.
+// Failures during continuation are logged and the routes gathered so far are kept. usage is the
+// token usage so far; every turn adds to it.
+func (c *Client) continueAnalysis(ctx context.Context, claudeReq ClaudeRequest, toolUse *Content, analysis *models.AnalysisResponse, req models.AnalysisRequest, usage Usage) *models.AnalysisResponse {
+	defer func() { analysis.Usage = usage.toModel() }()
+
+	seen := make(map[string]bool)
+	for _, routes := range [][]models.APIRoute{analysis.NewRoutes, analysis.ModifiedRoutes, analysis.DeletedRoutes} {
+		for _, route := range routes {
+			seen[routeKey(route)] = true
+		}
+	}
+
+	for turn := 1; turn <= c.config.MaxContinuationTurns; turn++ {
+		if isComplete(toolUse.Input) {
+			break
+		}
+
+		claudeReq.Messages = append(claudeReq.Messages,
+			Message{Role: "assistant", Content: []ContentBlock{{
+				Type:  "tool_use",
+				ID:    toolUse.ID,
+				Name:  toolUse.Name,
+				Input: toolUse.Input,
+			}}},
+			Message{Role: "user", Content: []ContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: toolUse.ID,
+				Content:   continuationPrompt,
+			}}},
+		)
+
+		next, turnUsage, err := c.sendToolRequest(ctx, claudeReq, "analyze_api_changes", req.Repository.FullName)
+		usage.add(turnUsage)
+		if err != nil {
+			c.logger.Warn("Claude continuation turn failed, keeping routes gathered so far", "turn", turn, "error", err)
+			break
+		}
+
+		more, err := c.convertToolInputToAnalysis(next.Input, turnUsage)
+		if err != nil {
+			c.logger.Warn("Failed to convert Claude continuation response", "turn", turn, "error", err)
+			break
+		}
+
+		added := 0
+		analysis.NewRoutes, added = appendUnseen(analysis.NewRoutes, more.NewRoutes, seen, added)
+		analysis.ModifiedRoutes, added = appendUnseen(analysis.ModifiedRoutes, more.ModifiedRoutes, seen, added)
+		analysis.DeletedRoutes, added = appendUnseen(analysis.DeletedRoutes, more.DeletedRoutes, seen, added)
+
+		c.logger.Info("Claude continuation turn completed", "turn", turn, "routes_added", added, "pr_number", req.PullRequest.Number)
+
+		if added == 0 {
+			break
+		}
+		toolUse = next
+	}
+
+	return analysis
+}
+
+// appendUnseen appends routes not reported in earlier turns
+func appendUnseen(dst, src []models.APIRoute, seen map[string]bool, added int) ([]models.APIRoute, int) {
+	for _, route := range src {
+		key := routeKey(route)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		dst = append(dst, route)
+		added++
+	}
+	return dst, added
+}
+
+func routeKey(route models.APIRoute) string {
+	return strings.ToUpper(route.Method) + " " + route.Path
+}
+
+// isComplete reports whether the tool input explicitly marked the analysis as complete
+func isComplete(input map[string]any) bool {
+	complete, ok := input["complete"].(bool)
+	return ok && complete
+}
@@ -0,0 +1,43 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/pkg/diffparse"
+)
+
+// declarativeGuidance explains how each kind of declarative file defines routes
+var declarativeGuidance = map[string]string{
+	diffparse.KindOpenAPI: "OpenAPI/Swagger: every operation (get, post, ...) under `paths` is a route. Added or removed paths/operations are new/deleted routes; " +
+		"changed parameters, requestBody, responses or security are modifications. Resolve `$ref` schemas when they are in the diff.",
+	diffparse.KindTerraform: "Terraform: aws_api_gateway_resource/aws_api_gateway_method pairs, aws_apigatewayv2_route (`route_key = \"GET /users\"`), " +
+		"google_api_gateway_* and azurerm_api_management_api_operation resources declare routes. Build the full path from parent resources.",
+	diffparse.KindServerless: "serverless.yml: each `events` entry of type `http` or `httpApi` under `functions` is a route (`path` and `method`). " +
+		"Request parameters and authorizers configured there apply to the route.",
+	diffparse.KindCloudFormation: "CloudFormation/SAM: `Api`/`HttpApi` events of AWS::Serverless::Function and AWS::ApiGateway::Method resources declare routes; " +
+		"an inline DefinitionBody is an OpenAPI document.",
+}
+
+// declarativeFilesContext lists declarative files in the diff with how to read routes from them
+func declarativeFilesContext(files []diffparse.DeclarativeFile) string {
+	var b strings.Builder
+	b.WriteString("\n**Declarative API Definitions in this Diff:**\n")
+
+	kinds := make(map[string]bool)
+	var order []string
+	for _, file := range files {
+		fmt.Fprintf(&b, "- %s (%s)\n", file.Path, file.Kind)
+		if !kinds[file.Kind] {
+			kinds[file.Kind] = true
+			order = append(order, file.Kind)
+		}
+	}
+
+	b.WriteString("\nThese files define routes through configuration, not code. Report route changes in them like code changes:\n")
+	for _, kind := range order {
+		fmt.Fprintf(&b, "- %s\n", declarativeGuidance[kind])
+	}
+	b.WriteString("If the same route changes in both code and a declarative file, report it once.\n")
+	return b.String()
+}
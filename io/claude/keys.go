@@ -0,0 +1,109 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// apiKey is a pooled Claude API key with its own circuit breaker
+type apiKey struct {
+	value   string
+	label   string // safe identifier for logs, never the key itself
+	breaker *gobreaker.CircuitBreaker
+}
+
+// keyPool hands out API keys round-robin, skipping keys whose circuit breaker is open
+type keyPool struct {
+	keys   []*apiKey
+	next   atomic.Uint64
+	labels *atomic.Uint64 // shared across rotations, so a label is never given to two keys
+}
+
+// newKeyPool pools values. Keys already in previous (nil for the first pool) keep their label and
+// circuit breaker; new keys are labelled after every key seen so far.
+func newKeyPool(values []string, previous *keyPool, logger interfaces.Logger) *keyPool {
+	pool := &keyPool{labels: new(atomic.Uint64)}
+	existing := make(map[string]*apiKey)
+	if previous != nil {
+		pool.labels = previous.labels
+		for _, key := range previous.keys {
+			existing[key.value] = key
+		}
+	}
+
+	for _, value := range values {
+		if key, ok := existing[value]; ok {
+			pool.keys = append(pool.keys, key)
+			continue
+		}
+		pool.keys = append(pool.keys, newAPIKey(value, fmt.Sprintf("key-%d", pool.labels.Add(1)), logger))
+	}
+	return pool
+}
+
+func newAPIKey(value, label string, logger interfaces.Logger) *apiKey {
+	return &apiKey{
+		value: value,
+		label: label,
+		breaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        CircuitBreakerName + "-" + label,
+			MaxRequests: 1,
+			Interval:    CircuitBreakerInterval,
+			Timeout:     CircuitBreakerTimeout,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= ConsecutiveFailureThreshold
+			},
+			// Only failures specific to this key count against it
+			IsSuccessful: func(err error) bool {
+				return err == nil || !isKeyError(err)
+			},
+			OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+				logger.Info("Claude API key circuit breaker state changed",
+					"name", name,
+					"from", from.String(),
+					"to", to.String(),
+				)
+			},
+		}),
+	}
+}
+
+// candidates returns available keys in round-robin order, starting after the last key handed out
+func (p *keyPool) candidates() []*apiKey {
+	if len(p.keys) == 0 {
+		return nil
+	}
+
+	start := int(p.next.Add(1)-1) % len(p.keys)
+	available := make([]*apiKey, 0, len(p.keys))
+	for i := range p.keys {
+		key := p.keys[(start+i)%len(p.keys)]
+		if key.breaker.State() != gobreaker.StateOpen {
+			available = append(available, key)
+		}
+	}
+	return available
+}
+
+// isKeyError reports whether err is caused by the key itself (invalid or rate limited),
+// in which case another key in the pool may still succeed
+func isKeyError(err error) bool {
+	var appErr *pkgerrors.AppError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	return appErr.Type == pkgerrors.ErrorTypeUnauthorized || appErr.Type == pkgerrors.ErrorTypeRateLimit
+}
+
+// SetAPIKeys replaces the key pool used by subsequent requests, e.g. after the secret manager
+// rotated the keys. Keys already in the pool keep their label and circuit breaker state, and new
+// keys get labels no earlier key had.
+func (c *Client) SetAPIKeys(values []string) {
+	c.keys.Store(newKeyPool(values, c.keys.Load(), c.logger))
+}
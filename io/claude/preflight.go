@@ -0,0 +1,48 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// ModelsEndpoint is a cheap authenticated endpoint used to verify connectivity and credentials
+const ModelsEndpoint = "/v1/models"
+
+// Preflight verifies that every configured API key can reach the Claude API.
+// It bypasses the circuit breakers so startup checks never trip them.
+func (c *Client) Preflight(ctx context.Context) error {
+	for _, key := range c.keys.Load().keys {
+		if err := c.checkKey(ctx, key.value); err != nil {
+			return fmt.Errorf("claude API key %s: %w", key.label, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) checkKey(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.BaseURL+ModelsEndpoint+"?limit=1", nil)
+	if err != nil {
+		return pkgerrors.NewExternalError("claude", "failed to create request").WithCause(err)
+	}
+	req.Header.Set(APIKeyHeader, key)
+	req.Header.Set(VersionHeader, AnthropicVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pkgerrors.NewExternalError("claude", err.Error()).WithCause(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return pkgerrors.NewUnauthorizedError("Invalid Claude API key").WithCode(pkgerrors.CodeClaudeUnauthorized)
+	case resp.StatusCode >= 400:
+		return pkgerrors.NewExternalError("claude", fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	return nil
+}
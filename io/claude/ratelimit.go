@@ -0,0 +1,65 @@
+package claude
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Anthropic rate-limit response headers
+const (
+	RequestsRemainingHeader = "anthropic-ratelimit-requests-remaining"
+	RequestsResetHeader     = "anthropic-ratelimit-requests-reset"
+	RetryAfterHeader        = "retry-after"
+)
+
+// rateLimitListeners fans out the request quota reported by Claude responses
+type rateLimitListeners struct {
+	mu        sync.Mutex
+	listeners []func(remaining int, reset time.Time)
+}
+
+// OnRateLimit registers fn to be called with the remaining request quota and its reset time
+// after every Claude response that reports them
+func (c *Client) OnRateLimit(fn func(remaining int, reset time.Time)) {
+	c.rateLimits.mu.Lock()
+	defer c.rateLimits.mu.Unlock()
+	c.rateLimits.listeners = append(c.rateLimits.listeners, fn)
+}
+
+// observeRateLimit reads the quota from a response. A 429 with Retry-After reports an
+// exhausted quota until the retry time.
+func (c *Client) observeRateLimit(resp *http.Response) {
+	remaining, reset, ok := parseRateLimit(resp.Header, resp.StatusCode, time.Now())
+	if !ok {
+		return
+	}
+
+	c.rateLimits.mu.Lock()
+	listeners := append([]func(int, time.Time){}, c.rateLimits.listeners...)
+	c.rateLimits.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(remaining, reset)
+	}
+}
+
+func parseRateLimit(header http.Header, statusCode int, now time.Time) (int, time.Time, bool) {
+	if statusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(header.Get(RetryAfterHeader))); err == nil {
+			return 0, now.Add(time.Duration(seconds) * time.Second), true
+		}
+	}
+
+	remaining, err := strconv.Atoi(strings.TrimSpace(header.Get(RequestsRemainingHeader)))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	reset, err := time.Parse(time.RFC3339, strings.TrimSpace(header.Get(RequestsResetHeader)))
+	if err != nil {
+		reset = now.Add(time.Minute)
+	}
+	return remaining, reset, true
+}
@@ -0,0 +1,56 @@
+package claude
+
+import (
+	"regexp"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const (
+	omittedPlaceholder  = "[omitted]"
+	redactedPlaceholder = "[REDACTED]"
+)
+
+// promptFilter applies the prompt data-governance settings to analysis requests
+type promptFilter struct {
+	cfg      config.PromptConfig
+	patterns []*regexp.Regexp
+}
+
+// newPromptFilter compiles the redaction patterns; invalid ones are rejected at config load
+func newPromptFilter(cfg config.PromptConfig) *promptFilter {
+	f := &promptFilter{cfg: cfg}
+	for _, pattern := range cfg.RedactPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			f.patterns = append(f.patterns, re)
+		}
+	}
+	return f
+}
+
+// apply returns a copy of req with excluded fields omitted and redaction patterns applied.
+// It's only used to build prompts, so metrics and logs still see the real repository name.
+func (f *promptFilter) apply(req models.AnalysisRequest) models.AnalysisRequest {
+	if !f.cfg.IncludeTitle {
+		req.PullRequest.Title = omittedPlaceholder
+	}
+	if !f.cfg.IncludeBody {
+		req.PullRequest.Body = omittedPlaceholder
+	}
+	if !f.cfg.IncludeRepoName {
+		req.Repository.FullName = omittedPlaceholder
+		req.PullRequest.DiffURL = omittedPlaceholder
+	}
+
+	req.PullRequest.Body = f.redact(req.PullRequest.Body)
+	req.Diff = f.redact(req.Diff)
+	return req
+}
+
+func (f *promptFilter) redact(text string) string {
+	for _, re := range f.patterns {
+		text = re.ReplaceAllString(text, redactedPlaceholder)
+	}
+	return text
+}
@@ -0,0 +1,120 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+	// jsonSchemaTypes are the primitive types allowed by the JSON Schema meta-schema
+	jsonSchemaTypes = map[string]bool{
+		"array":   true,
+		"boolean": true,
+		"integer": true,
+		"null":    true,
+		"number":  true,
+		"object":  true,
+		"string":  true,
+	}
+)
+
+// ValidateToolSchemas validates every tool definition sent to Claude, with analysisRequired as the
+// required fields of the analysis tool. It is meant to run at startup so a malformed schema or an
+// unknown CLAUDE_REQUIRED_FIELDS entry fails fast instead of surfacing as a Claude API error.
+func ValidateToolSchemas(analysisRequired []string) error {
+	for _, tool := range []Tool{buildAnalysisToolSchema(analysisRequired), buildTriageToolSchema()} {
+		if err := ValidateToolSchema(tool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateToolSchema checks a tool definition against the subset of the JSON Schema meta-schema we use
+func ValidateToolSchema(tool Tool) error {
+	if !toolNamePattern.MatchString(tool.Name) {
+		return fmt.Errorf("tool %q: name must match %s", tool.Name, toolNamePattern)
+	}
+	if strings.TrimSpace(tool.Description) == "" {
+		return fmt.Errorf("tool %q: description is required", tool.Name)
+	}
+
+	// Round-trip through JSON to make sure the schema serializes the way the API expects
+	raw, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("tool %q: failed to marshal input schema: %w", tool.Name, err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("tool %q: input schema is not a JSON object: %w", tool.Name, err)
+	}
+
+	if tool.InputSchema.Type != "object" {
+		return fmt.Errorf("tool %q: input_schema.type must be \"object\", got %q", tool.Name, tool.InputSchema.Type)
+	}
+
+	root := Property{
+		Type:       tool.InputSchema.Type,
+		Properties: tool.InputSchema.Properties,
+		Required:   tool.InputSchema.Required,
+	}
+	if err := validateProperty("input_schema", root); err != nil {
+		return fmt.Errorf("tool %q: %w", tool.Name, err)
+	}
+
+	return nil
+}
+
+func validateProperty(path string, prop Property) error {
+	if !jsonSchemaTypes[prop.Type] {
+		return fmt.Errorf("%s: invalid type %q", path, prop.Type)
+	}
+
+	switch prop.Type {
+	case "array":
+		if prop.Items == nil {
+			return fmt.Errorf("%s: array type requires items", path)
+		}
+		if err := validateProperty(path+".items", *prop.Items); err != nil {
+			return err
+		}
+	case "object":
+		// Iterate in a stable order so errors are deterministic
+		names := make([]string, 0, len(prop.Properties))
+		for name := range prop.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("%s: property names must not be empty", path)
+			}
+			if err := validateProperty(path+".properties."+name, prop.Properties[name]); err != nil {
+				return err
+			}
+		}
+	default:
+		if prop.Items != nil || len(prop.Properties) > 0 {
+			return fmt.Errorf("%s: %s type must not declare items or properties", path, prop.Type)
+		}
+	}
+
+	seen := make(map[string]bool, len(prop.Required))
+	for _, name := range prop.Required {
+		if seen[name] {
+			return fmt.Errorf("%s: required field %q is listed twice", path, name)
+		}
+		seen[name] = true
+		if _, ok := prop.Properties[name]; !ok {
+			return fmt.Errorf("%s: required field %q is not defined in properties", path, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package claude
+
+import "encoding/json"
+
+// toolSchemaPreview is the request-level part of analysis calls that doesn't depend on the PR
+type toolSchemaPreview struct {
+	Model      string `json:"model"`
+	MaxTokens  int    `json:"max_tokens"`
+	System     string `json:"system,omitempty"`
+	Tools      []Tool `json:"tools"`
+	ToolChoice any    `json:"tool_choice"`
+	// TriageTools are sent instead of Tools when USE_DESCRIPTION_FIRST triages the PR description
+	TriageTools []Tool `json:"triage_tools"`
+}
+
+// ToolSchema returns the tool schema exactly as it is serialized in analysis requests,
+// optionally with the system prompt
+func (c *Client) ToolSchema(includeSystemPrompt bool) (json.RawMessage, error) {
+	preview := toolSchemaPreview{
+		Model:     c.config.Model,
+		MaxTokens: c.config.MaxTokens,
+		Tools:     []Tool{buildAnalysisToolSchema(c.config.RequiredFields)},
+		ToolChoice: map[string]any{
+			"type": "tool",
+			"name": "analyze_api_changes",
+		},
+		TriageTools: []Tool{buildTriageToolSchema()},
+	}
+	if includeSystemPrompt {
+		preview.System = systemPrompt
+	}
+	return json.Marshal(preview)
+}
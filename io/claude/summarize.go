@@ -0,0 +1,265 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/backoff"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	summarizeToolName  = "summarize_diff"
+	summarizeMaxTokens = 2048
+	// summarizeOverhead is the size of the summarization instructions around a diff chunk
+	summarizeOverhead = 4096
+	// minDiffBudget is the least room a diff summary needs in the analysis prompt to be useful
+	minDiffBudget = 1024
+)
+
+const summarizePrompt = `This is part %d of %d of a GitHub Pull Request diff that is too large to analyze in one request.
+Summarize it for a later pass that will detect REST API changes from your summary alone.
+
+Keep, as close to verbatim as possible:
+- every HTTP route definition that is added, removed or changed (method, path, file and line)
+- handler signatures, request/response structs and schemas, parameter parsing and validation
+- authentication/authorization middleware changes
+- routes declared in OpenAPI specs, Terraform, serverless.yml or CloudFormation files
+
+Drop everything else (tests, docs, formatting, unrelated refactors), but list the files you dropped.
+Call the summarize_diff tool with the summary.
+
+**Diff (part %d of %d):**
+%s`
+
+// diffSummary is the summarized diff and how many of its chunks made it in
+type diffSummary struct {
+	text     string
+	chunks   int
+	excluded int // chunks that failed on every attempt and were left out
+}
+
+// coverage is the share of the diff the summary covers
+func (s diffSummary) coverage() float64 {
+	if s.chunks == 0 {
+		return 1
+	}
+	return float64(s.chunks-s.excluded) / float64(s.chunks)
+}
+
+// fitDiffBudget returns the bytes left for the diff summary in the analysis prompt of req. When the
+// prompt around the diff leaves less than minDiffBudget, the existing-routes context is trimmed until
+// it fits; a prompt still too large without any existing routes is an error.
+func (c *Client) fitDiffBudget(req models.AnalysisRequest) (models.AnalysisRequest, int, error) {
+	budget := func(req models.AnalysisRequest) int {
+		return c.config.MaxPromptBytes - (len(buildAnalysisPrompt(req)) - len(req.Diff))
+	}
+
+	available := budget(req)
+	total := len(req.ExistingRoutes)
+	for available < minDiffBudget && len(req.ExistingRoutes) > 0 {
+		req.ExistingRoutes = req.ExistingRoutes[:len(req.ExistingRoutes)/2]
+		available = budget(req)
+	}
+	if available < minDiffBudget {
+		return req, 0, pkgerrors.NewValidationError(fmt.Sprintf(
+			"prompt without the diff needs %d of CLAUDE_MAX_PROMPT_BYTES=%d bytes, leaving less than %d for the diff",
+			c.config.MaxPromptBytes-available, c.config.MaxPromptBytes, minDiffBudget)).WithCode(pkgerrors.CodePromptTooLarge)
+	}
+	if len(req.ExistingRoutes) < total {
+		c.logger.Warn("Trimmed existing routes from the prompt to make room for the diff summary",
+			"pr_number", req.PullRequest.Number,
+			"existing_routes", total,
+			"kept_routes", len(req.ExistingRoutes),
+			"max_prompt_bytes", c.config.MaxPromptBytes,
+		)
+	}
+	return req, available, nil
+}
+
+// summarizeDiff replaces a diff too large for one prompt with Claude's summary of its API-relevant
+// parts. The diff is split at file boundaries into chunks that fit the prompt, which are summarized
+// concurrently within the shared chunk limiter. A chunk failing CLAUDE_CHUNK_RETRIES retries is left
+// out, lowering the coverage; only when every chunk fails does the summary fail. The summary is cut
+// to budget bytes, on a rune boundary, if it's still too large.
+func (c *Client) summarizeDiff(ctx context.Context, req models.AnalysisRequest, budget int) (diffSummary, Usage, error) {
+	model := c.config.SummaryModel
+	if model == "" {
+		model = c.config.ModelFor(req.Repository.FullName)
+	}
+	maxTokens := summarizeMaxTokens
+	if c.config.MaxTokens < maxTokens {
+		maxTokens = c.config.MaxTokens
+	}
+
+	chunks := splitDiff(req.Diff, c.config.MaxPromptBytes-summarizeOverhead)
+	c.logger.Warn("Prompt exceeds maximum size, summarizing diff before analysis",
+		"pr_number", req.PullRequest.Number,
+		"diff_size_bytes", len(req.Diff),
+		"max_prompt_bytes", c.config.MaxPromptBytes,
+		"chunks", len(chunks),
+		"model", model,
+	)
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		usage     Usage
+		summaries = make([]string, len(chunks))
+		errs      = make([]error, len(chunks))
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			summary, chunkUsage, err := c.summarizeChunk(ctx, req, model, maxTokens, i, len(chunks), chunk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			usage.add(chunkUsage)
+			summaries[i], errs[i] = summary, err
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return diffSummary{}, usage, err
+	}
+
+	result := diffSummary{chunks: len(chunks)}
+	parts := make([]string, 0, len(chunks))
+	var firstErr error
+	for i, err := range errs {
+		if err == nil {
+			parts = append(parts, fmt.Sprintf("### Part %d of %d\n%s", i+1, len(chunks), strings.TrimSpace(summaries[i])))
+			continue
+		}
+		if firstErr == nil {
+			firstErr = pkgerrors.WrapError(err, fmt.Sprintf("failed to summarize diff part %d of %d", i+1, len(chunks)))
+		}
+		result.excluded++
+		c.metrics.IncrementCounter("claude_chunks_total", map[string]string{"outcome": "excluded"})
+		c.logger.Warn("Leaving diff part out of the analysis", "pr_number", req.PullRequest.Number, "part", i+1, "chunks", len(chunks), "error", err)
+		parts = append(parts, fmt.Sprintf("### Part %d of %d\n[This part could not be summarized; API changes in it are unknown.]", i+1, len(chunks)))
+	}
+	if result.excluded == len(chunks) {
+		return diffSummary{}, usage, firstErr
+	}
+
+	result.text = "NOTE: the diff was too large to analyze directly; this is a summary of its API-relevant changes.\n\n" +
+		strings.Join(parts, "\n\n")
+	if len(result.text) > budget {
+		const marker = "\n[summary truncated]"
+		result.text = strings.ToValidUTF8(result.text[:budget-len(marker)], "") + marker
+	}
+	return result, usage, nil
+}
+
+// summarizeChunk summarizes part i of n, retrying failures with backoff. Each attempt holds a slot
+// of the chunk limiter, which also backs off while Claude reports the request quota running out.
+func (c *Client) summarizeChunk(ctx context.Context, req models.AnalysisRequest, model string, maxTokens, i, n int, chunk string) (string, Usage, error) {
+	claudeReq := ClaudeRequest{
+		Model:     model,
+		MaxTokens: maxTokens,
+		Messages: []Message{{
+			Role:    "user",
+			Content: fmt.Sprintf(summarizePrompt, i+1, n, i+1, n, chunk),
+		}},
+		System: systemPrompt,
+		Tools:  []Tool{buildSummarizeToolSchema()},
+		ToolChoice: map[string]any{
+			"type": "tool",
+			"name": summarizeToolName,
+		},
+	}
+
+	var (
+		usage   Usage
+		summary string
+	)
+	retryable := func(error) bool { return ctx.Err() == nil }
+	err := backoff.Retry(ctx, backoff.DefaultPolicy, c.config.ChunkRetries+1, retryable, func(attempt int) error {
+		if attempt > 0 {
+			c.metrics.IncrementCounter("claude_chunks_total", map[string]string{"outcome": "retried"})
+		}
+		if err := c.chunks.Acquire(ctx); err != nil {
+			return err
+		}
+		defer c.chunks.Release()
+
+		toolUse, attemptUsage, err := c.sendToolRequest(ctx, claudeReq, summarizeToolName, req.Repository.FullName)
+		usage.add(attemptUsage)
+		if err != nil {
+			return err
+		}
+		summary, _ = toolUse.Input["summary"].(string)
+		return nil
+	})
+	if err != nil {
+		return "", usage, err
+	}
+	c.metrics.IncrementCounter("claude_chunks_total", map[string]string{"outcome": "summarized"})
+	return summary, usage, nil
+}
+
+func buildSummarizeToolSchema() Tool {
+	return Tool{
+		Name:        summarizeToolName,
+		Description: "Return a summary of the API-relevant changes in a part of a Pull Request diff",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"summary": {Type: "string", Description: "API-relevant changes, keeping route definitions and schemas close to verbatim"},
+			},
+			Required: []string{"summary"},
+		},
+	}
+}
+
+// splitDiff packs whole files of diff into chunks of at most size bytes, cutting files larger
+// than size on line boundaries
+func splitDiff(diff string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, file := range splitFiles(diff) {
+		for len(file) > size {
+			cut := strings.LastIndex(file[:size], "\n") + 1
+			if cut <= 0 {
+				cut = size
+			}
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			chunks = append(chunks, file[:cut])
+			file = file[cut:]
+		}
+		if current.Len()+len(file) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(file)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitFiles splits a git diff before every "diff --git" header
+func splitFiles(diff string) []string {
+	var files []string
+	for diff != "" {
+		next := strings.Index(diff, "\ndiff --git ")
+		if next < 0 {
+			files = append(files, diff)
+			break
+		}
+		files = append(files, diff[:next+1])
+		diff = diff[next+1:]
+	}
+	return files
+}
@@ -0,0 +1,137 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	triageToolName  = "triage_pr_description"
+	triageMaxTokens = 1024
+)
+
+// TriageDescription asks Claude to extract intended API changes from the PR title and body only
+func (c *Client) TriageDescription(ctx context.Context, req models.AnalysisRequest) (*models.DescriptionTriage, error) {
+	startTime := time.Now()
+	labels := map[string]string{
+		"service":    "claude",
+		"operation":  "triage_description",
+		"repository": req.Repository.FullName,
+	}
+
+	result, err := c.circuitBreaker.Execute(func() (any, error) {
+		return c.executeTriage(ctx, req)
+	})
+
+	duration := time.Since(startTime).Seconds()
+	c.metrics.RecordDuration("claude_request_duration_seconds", duration, labels)
+
+	if err != nil {
+		labels["status"] = "error"
+		c.metrics.IncrementCounter("claude_requests_total", labels)
+		c.logger.Error("Failed to triage PR description with Claude", err, "pr_number", req.PullRequest.Number)
+		return nil, err
+	}
+
+	labels["status"] = "success"
+	c.metrics.IncrementCounter("claude_requests_total", labels)
+
+	triage := result.(*models.DescriptionTriage)
+	c.logger.Info("Triaged PR description with Claude",
+		"pr_number", req.PullRequest.Number,
+		"has_api_changes", triage.HasAPIChanges,
+		"intended_changes", len(triage.IntendedChanges),
+		"duration_ms", duration*1000,
+	)
+
+	return triage, nil
+}
+
+func (c *Client) executeTriage(ctx context.Context, req models.AnalysisRequest) (*models.DescriptionTriage, error) {
+	maxTokens := triageMaxTokens
+	if c.config.MaxTokens < maxTokens {
+		maxTokens = c.config.MaxTokens
+	}
+
+	claudeReq := ClaudeRequest{
+		Model:     c.config.Model,
+		MaxTokens: maxTokens,
+		Messages: []Message{
+			{
+				Role:    "user",
+				Content: buildTriagePrompt(c.promptFilter.apply(req)),
+			},
+		},
+		System: systemPrompt,
+		Tools:  []Tool{buildTriageToolSchema()},
+		ToolChoice: map[string]any{
+			"type": "tool",
+			"name": triageToolName,
+		},
+	}
+
+	toolUse, _, err := c.sendToolRequest(ctx, claudeReq, triageToolName, req.Repository.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(toolUse.Input)
+	if err != nil {
+		return nil, pkgerrors.WrapError(err, "failed to marshal triage tool input")
+	}
+
+	var triage models.DescriptionTriage
+	if err := json.Unmarshal(jsonData, &triage); err != nil {
+		return nil, pkgerrors.WrapError(err, "failed to convert Claude response to triage")
+	}
+
+	return &triage, nil
+}
+
+func buildTriagePrompt(req models.AnalysisRequest) string {
+	return fmt.Sprintf(`
+Based ONLY on the title and description of this GitHub Pull Request, determine whether it intends to change any HTTP API routes.
+
+**Pull Request Details:**
+- **Title:** %s
+- **Description:** %s
+- **Repository:** %s
+
+If the description does not clearly rule out API changes, set has_api_changes to true.
+List each intended API change (e.g. "POST /api/v1/users added") in intended_changes.
+
+**Expected Output:** Use the %s tool.
+`, req.PullRequest.Title, req.PullRequest.Body, req.Repository.FullName, triageToolName)
+}
+
+// buildTriageToolSchema creates the JSON schema for the description triage tool
+func buildTriageToolSchema() Tool {
+	return Tool{
+		Name:        triageToolName,
+		Description: "Classify whether a Pull Request description indicates API route changes and list the intended changes",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"has_api_changes": {
+					Type:        "boolean",
+					Description: "Whether the PR is expected to add, modify or remove API routes",
+				},
+				"intended_changes": {
+					Type:        "array",
+					Description: "Short descriptions of each intended API change",
+					Items:       &Property{Type: "string"},
+				},
+				"summary": {
+					Type:        "string",
+					Description: "One sentence summary of the PR intent",
+				},
+			},
+			Required: []string{"has_api_changes", "intended_changes", "summary"},
+		},
+	}
+}
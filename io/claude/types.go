@@ -12,8 +12,19 @@ type ClaudeRequest struct {
 
 // Message represents a message in the Claude conversation
 type Message struct {
-	Role    string `json:"role"` // "user" or "assistant"
-	Content string `json:"content"`
+	Role    string `json:"role"`    // "user" or "assistant"
+	Content any    `json:"content"` // string or []ContentBlock
+}
+
+// ContentBlock represents a structured content block sent to Claude (tool_use, tool_result, text)
+type ContentBlock struct {
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	ID        string         `json:"id,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Input     map[string]any `json:"input,omitempty"`
+	ToolUseID string         `json:"tool_use_id,omitempty"`
+	Content   string         `json:"content,omitempty"`
 }
 
 // Tool represents a function tool that Claude can call
@@ -37,6 +48,9 @@ type Property struct {
 	Items       *Property           `json:"items,omitempty"`
 	Properties  map[string]Property `json:"properties,omitempty"`
 	Required    []string            `json:"required,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
+	// AdditionalProperties describes the values of an object keyed by arbitrary names
+	AdditionalProperties *Property `json:"additionalProperties,omitempty"`
 }
 
 // ClaudeResponse represents the response from Claude API
@@ -54,6 +68,7 @@ type ClaudeResponse struct {
 // Content represents the content in Claude's response
 type Content struct {
 	Type  string         `json:"type"`
+	ID    string         `json:"id,omitempty"`
 	Text  string         `json:"text,omitempty"`
 	Name  string         `json:"name,omitempty"`
 	Input map[string]any `json:"input,omitempty"`
@@ -61,8 +76,10 @@ type Content struct {
 
 // Usage represents token usage information
 type Usage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
 // ClaudeError represents an error response from Claude API
@@ -0,0 +1,57 @@
+package claude
+
+import "github.com/igorsal/pr-documentator/internal/models"
+
+// recordUsage records token counters and the cumulative estimated cost for a Claude response
+func (c *Client) recordUsage(repository, model string, usage Usage) {
+	labels := map[string]string{
+		"repository": repository,
+		"model":      model,
+	}
+
+	c.metrics.AddCounter("claude_input_tokens_total", float64(usage.InputTokens), labels)
+	c.metrics.AddCounter("claude_output_tokens_total", float64(usage.OutputTokens), labels)
+
+	pricing, ok := c.config.Pricing[model]
+	if !ok {
+		c.logger.Debug("No pricing configured for Claude model, skipping cost estimate", "model", model)
+		return
+	}
+
+	cost := float64(usage.InputTokens)/1_000_000*pricing.InputPerMillion +
+		float64(usage.OutputTokens)/1_000_000*pricing.OutputPerMillion
+
+	c.costMu.Lock()
+	key := repository + "|" + model
+	c.costTotals[key] += cost
+	total := c.costTotals[key]
+	c.costMu.Unlock()
+
+	c.metrics.SetGauge("claude_estimated_cost_usd", total, labels)
+
+	c.logger.Debug("Recorded Claude token usage",
+		"repository", repository,
+		"model", model,
+		"input_tokens", usage.InputTokens,
+		"output_tokens", usage.OutputTokens,
+		"estimated_cost_usd", cost,
+	)
+}
+
+// add sums the usage of several requests made for one analysis
+func (u *Usage) add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+}
+
+// toModel converts the usage for the analysis response
+func (u Usage) toModel() *models.TokenUsage {
+	return &models.TokenUsage{
+		InputTokens:              u.InputTokens,
+		OutputTokens:             u.OutputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens,
+	}
+}
@@ -0,0 +1,239 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/backoff"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	// MaxAnnotations is the most annotations GitHub accepts per Check Run request
+	MaxAnnotations = 50
+	APIVersion     = "2022-11-28"
+	maxAttempts    = 3
+)
+
+// CheckRunRequest is the body of POST /repos/{owner}/{repo}/check-runs
+type CheckRunRequest struct {
+	Name       string         `json:"name"`
+	HeadSHA    string         `json:"head_sha"`
+	Status     string         `json:"status"`
+	Conclusion string         `json:"conclusion"`
+	Output     CheckRunOutput `json:"output"`
+}
+
+// CheckRunOutput is the title, summary and annotations shown in the PR checks UI
+type CheckRunOutput struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation points at the line where a route changed
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// ChecksClient publishes analysis results as GitHub Check Runs without blocking the caller
+type ChecksClient struct {
+	httpClient *http.Client
+	config     config.GitHubConfig
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+	inFlight   sync.WaitGroup
+	token      atomic.Value // string; replaced by SetToken when the secret manager rotates it
+}
+
+// NewChecksClient creates a Check Run publisher authenticated with cfg.Token
+func NewChecksClient(cfg config.GitHubConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *ChecksClient {
+	c := &ChecksClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		config:     cfg,
+		logger:     logger,
+		metrics:    metrics,
+	}
+	c.token.Store(cfg.Token)
+	return c
+}
+
+// SetToken replaces the token used by subsequent Check Runs, e.g. after the secret manager
+// rotated it
+func (c *ChecksClient) SetToken(token string) {
+	c.token.Store(token)
+}
+
+// Notify creates a Check Run on the PR head commit in the background
+func (c *ChecksClient) Notify(ctx context.Context, resp *models.AnalysisResponse) {
+	if resp.HeadSHA == "" || !strings.Contains(resp.Repository, "/") {
+		c.logger.Debug("Skipping Check Run for analysis without a head commit", "repo", resp.Repository)
+		return
+	}
+
+	body, err := json.Marshal(c.buildCheckRun(resp))
+	if err != nil {
+		c.logger.Error("Failed to marshal Check Run", err)
+		return
+	}
+
+	c.inFlight.Add(1)
+	go func() {
+		defer c.inFlight.Done()
+
+		// Detach from the request context, which is cancelled once the response is written
+		createCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := backoff.Retry(createCtx, backoff.DefaultPolicy, maxAttempts, isRetryable, func(attempt int) error {
+			return c.createCheckRun(createCtx, resp.Repository, body)
+		})
+
+		status := "success"
+		if err != nil {
+			status = "error"
+			c.logger.Error("Failed to create GitHub Check Run", err, "repo", resp.Repository, "pr_number", resp.PRNumber)
+		}
+		c.metrics.IncrementCounter("github_check_runs_total", map[string]string{"status": status})
+	}()
+}
+
+// Wait blocks until in-flight Check Runs are created or ctx is done
+func (c *ChecksClient) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildCheckRun summarizes the analysis, concluding "success" when confidence reaches
+// CheckMinConfidence and "neutral" otherwise
+func (c *ChecksClient) buildCheckRun(resp *models.AnalysisResponse) CheckRunRequest {
+	conclusion := "success"
+	if resp.Confidence < c.config.CheckMinConfidence {
+		conclusion = "neutral"
+	}
+
+	title := fmt.Sprintf("%d new, %d modified, %d deleted API routes",
+		len(resp.NewRoutes), len(resp.ModifiedRoutes), len(resp.DeletedRoutes))
+	if n := len(resp.BreakingChanges); n > 0 {
+		title = fmt.Sprintf("%d breaking change(s): %s", n, title)
+	}
+
+	var summary strings.Builder
+	summary.WriteString(resp.Summary)
+	fmt.Fprintf(&summary, "\n\n**Confidence:** %.0f%%", resp.Confidence*100)
+	if resp.PostmanUpdate.Status != "" {
+		fmt.Fprintf(&summary, "\n**Postman:** %s", resp.PostmanUpdate.Status)
+	}
+	if len(resp.FlaggedRoutes) > 0 {
+		fmt.Fprintf(&summary, "\n**Held back for review:** %d low-confidence route(s)", len(resp.FlaggedRoutes))
+	}
+
+	var annotations []Annotation
+	for _, group := range []struct {
+		label  string
+		routes []models.APIRoute
+	}{
+		{"New", resp.NewRoutes},
+		{"Modified", resp.ModifiedRoutes},
+		{"Deleted", resp.DeletedRoutes},
+	} {
+		for _, route := range group.routes {
+			if route.SourceFile == "" || len(annotations) >= MaxAnnotations {
+				continue
+			}
+			line := route.SourceLine
+			if line <= 0 {
+				line = 1
+			}
+			annotations = append(annotations, Annotation{
+				Path:            route.SourceFile,
+				StartLine:       line,
+				EndLine:         line,
+				AnnotationLevel: "notice",
+				Title:           fmt.Sprintf("%s API route: %s %s", group.label, route.Method, route.Path),
+				Message:         route.Description,
+			})
+		}
+	}
+
+	return CheckRunRequest{
+		Name:       c.config.CheckRunName,
+		HeadSHA:    resp.HeadSHA,
+		Status:     "completed",
+		Conclusion: conclusion,
+		Output: CheckRunOutput{
+			Title:       title,
+			Summary:     summary.String(),
+			Annotations: annotations,
+		},
+	}
+}
+
+func (c *ChecksClient) createCheckRun(ctx context.Context, repository string, body []byte) error {
+	url := fmt.Sprintf("%s/repos/%s/check-runs", strings.TrimRight(c.config.APIURL, "/"), repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return pkgerrors.NewExternalError("github", "failed to create request").WithCause(err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	token, _ := c.token.Load().(string)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", APIVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pkgerrors.NewUnavailableError("github").WithCause(err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return pkgerrors.NewUnauthorizedError("GitHub token rejected or lacks checks:write permission")
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return pkgerrors.NewRateLimitError("github")
+	case resp.StatusCode >= 500:
+		return pkgerrors.NewUnavailableError("github").WithContext("status_code", resp.StatusCode)
+	default:
+		return pkgerrors.NewExternalError("github", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)))
+	}
+}
+
+// isRetryable retries transient failures but not client errors
+func isRetryable(err error) bool {
+	appErr, ok := pkgerrors.AsAppError(err)
+	if !ok {
+		return true
+	}
+	return appErr.Type == pkgerrors.ErrorTypeUnavailable || appErr.Type == pkgerrors.ErrorTypeRateLimit
+}
@@ -0,0 +1,276 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	DialTimeout    = 10 * time.Second
+	ClientName     = "pr-documentator"
+	subscriptionID = "1"
+	maxControlLine = 4096
+)
+
+// Client is a minimal NATS core client speaking the text protocol over TCP.
+// It supports a single queue-group subscription and publishing, which is all the
+// queue ingest mode needs.
+type Client struct {
+	config config.QueueConfig
+	logger interfaces.Logger
+
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// connectOptions is sent in the CONNECT handshake
+type connectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// NewClient connects to the NATS server configured in cfg
+func NewClient(ctx context.Context, cfg config.QueueConfig, logger interfaces.Logger) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, pkgerrors.NewValidationError("invalid NATS URL").WithCause(err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	dialer := &net.Dialer{Timeout: DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, pkgerrors.NewUnavailableError("nats").WithCause(err)
+	}
+
+	c := &Client{
+		config: cfg,
+		logger: logger,
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, 32*1024),
+	}
+
+	if err := c.handshake(u); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	logger.Info("Connected to NATS", "url", u.Redacted())
+	return c, nil
+}
+
+func (c *Client) handshake(u *url.URL) error {
+	// Server greets with INFO
+	line, err := c.readLine()
+	if err != nil {
+		return pkgerrors.NewExternalError("nats", "failed to read server info").WithCause(err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return pkgerrors.NewExternalError("nats", fmt.Sprintf("unexpected greeting: %q", line))
+	}
+
+	opts := connectOptions{
+		Name:    ClientName,
+		Lang:    "go",
+		Version: "0.1.0",
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			opts.User = u.User.Username()
+			opts.Pass = pass
+		} else {
+			opts.Token = u.User.Username()
+		}
+	}
+
+	payload, err := json.Marshal(opts)
+	if err != nil {
+		return pkgerrors.WrapError(err, "failed to marshal NATS connect options")
+	}
+
+	if err := c.write("CONNECT " + string(payload) + "\r\nPING\r\n"); err != nil {
+		return err
+	}
+
+	// The server answers PING with PONG once CONNECT is accepted, or -ERR otherwise
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return pkgerrors.NewExternalError("nats", "failed to complete handshake").WithCause(err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return pkgerrors.NewUnauthorizedError("NATS connection rejected: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// Consume subscribes to the configured subject and calls handler for every message until ctx is done.
+// Messages are handled one at a time by a worker goroutine, so the read loop keeps answering server
+// PINGs during long analyses. Up to QueueConfig.BufferSize messages wait for the worker; later ones
+// are dropped.
+func (c *Client) Consume(ctx context.Context, handler func(ctx context.Context, data []byte) error) error {
+	sub := fmt.Sprintf("SUB %s %s\r\n", c.config.Subject, subscriptionID)
+	if c.config.QueueGroup != "" {
+		sub = fmt.Sprintf("SUB %s %s %s\r\n", c.config.Subject, c.config.QueueGroup, subscriptionID)
+	}
+	if err := c.write(sub); err != nil {
+		return err
+	}
+
+	c.logger.Info("Subscribed to NATS subject", "subject", c.config.Subject, "queue_group", c.config.QueueGroup)
+
+	// Unblock the read loop when the context is cancelled
+	stop := context.AfterFunc(ctx, func() {
+		c.conn.Close()
+	})
+	defer stop()
+
+	bufferSize := c.config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	messages := make(chan []byte, bufferSize)
+	var worker sync.WaitGroup
+	worker.Add(1)
+	go func() {
+		defer worker.Done()
+		for data := range messages {
+			if ctx.Err() != nil {
+				continue
+			}
+			if err := handleSafely(ctx, handler, data); err != nil {
+				c.logger.Error("Failed to handle NATS message", err, "subject", c.config.Subject)
+			}
+		}
+	}()
+	// Messages buffered when the connection drops are still handled before reconnecting
+	defer func() {
+		close(messages)
+		worker.Wait()
+	}()
+
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return pkgerrors.NewUnavailableError("nats").WithCause(err)
+		}
+
+		switch {
+		case line == "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case line == "PONG", line == "+OK", strings.HasPrefix(line, "INFO "):
+			// Nothing to do
+		case strings.HasPrefix(line, "-ERR"):
+			c.logger.Warn("NATS server error", "error", line)
+		case strings.HasPrefix(line, "MSG "):
+			data, err := c.readPayload(line)
+			if err != nil {
+				return pkgerrors.NewExternalError("nats", "failed to read message").WithCause(err)
+			}
+			select {
+			case messages <- data:
+			default:
+				c.logger.Error("Dropping NATS message, analysis backlog is full", nil,
+					"subject", c.config.Subject,
+					"buffer_size", bufferSize,
+				)
+			}
+		}
+	}
+}
+
+// handleSafely runs handler, turning a panic into an error so one bad message can't stop the consumer
+func handleSafely(ctx context.Context, handler func(ctx context.Context, data []byte) error, data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, data)
+}
+
+// Publish sends data to the configured output subject
+func (c *Client) Publish(ctx context.Context, data []byte) error {
+	if c.config.OutputSubject == "" {
+		return nil
+	}
+	return c.write(fmt.Sprintf("PUB %s %d\r\n%s\r\n", c.config.OutputSubject, len(data), data))
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// readPayload reads the message body announced by a "MSG <subject> <sid> [reply-to] <#bytes>" line
+func (c *Client) readPayload(line string) ([]byte, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("malformed MSG line: %q", line)
+	}
+
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed MSG size: %w", err)
+	}
+
+	// Payload is followed by CRLF
+	buf := make([]byte, size+2)
+	if _, err := io.ReadFull(c.reader, buf); err != nil {
+		return nil, err
+	}
+	return buf[:size], nil
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) > maxControlLine {
+		return "", fmt.Errorf("control line exceeds %d bytes", maxControlLine)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *Client) write(data string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := io.WriteString(c.conn, data); err != nil {
+		return pkgerrors.NewUnavailableError("nats").WithCause(err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package postman
+
+import (
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// SetAuditLogger records Postman API key use and collection writes to audit
+func (c *Client) SetAuditLogger(audit interfaces.AuditLogger) {
+	c.audit = audit
+}
+
+// auditKeyUse records one Postman API call made with the configured key
+func (c *Client) auditKeyUse(operation string, statusCode int, err error) {
+	if c.audit == nil {
+		return
+	}
+	outcome := models.AuditOutcomeSuccess
+	details := map[string]any{"operation": operation}
+	if err != nil || statusCode >= 400 {
+		outcome = models.AuditOutcomeFailure
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	if statusCode > 0 {
+		details["status_code"] = statusCode
+	}
+	c.audit.Record(models.AuditEvent{
+		Action:   models.AuditActionCredentialUse,
+		Actor:    "pr-documentator",
+		Resource: "postman:api-key",
+		Outcome:  outcome,
+		Details:  details,
+	})
+}
+
+// auditWrite records a collection write and the PR that caused it
+func (c *Client) auditWrite(collectionID string, analysis *models.AnalysisResponse, update *models.PostmanUpdate, err error) {
+	if c.audit == nil {
+		return
+	}
+	event := models.AuditEvent{
+		Action:   models.AuditActionPostmanWrite,
+		Actor:    "pr-documentator",
+		Resource: "postman:collection/" + collectionID,
+		Outcome:  models.AuditOutcomeSuccess,
+		Details: map[string]any{
+			"repository": analysis.Repository,
+			"pr_number":  analysis.PRNumber,
+		},
+	}
+	if update != nil {
+		event.Details["items_added"] = update.ItemsAdded
+		event.Details["items_modified"] = update.ItemsModified
+		event.Details["items_deleted"] = update.ItemsDeleted
+	}
+	if err != nil {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Details["error"] = err.Error()
+	}
+	c.audit.Record(event)
+}
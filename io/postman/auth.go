@@ -0,0 +1,60 @@
+package postman
+
+import (
+	"fmt"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// buildAuth converts the route's auth into Postman request auth using collection variables for
+// secrets. Routes with unknown auth return nil and inherit the collection's auth.
+func buildAuth(auth *models.RouteAuth) *models.PostmanAuth {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Scheme {
+	case models.AuthSchemeNone:
+		return &models.PostmanAuth{Type: "noauth"}
+	case models.AuthSchemeBearer:
+		return &models.PostmanAuth{
+			Type:   "bearer",
+			Bearer: []models.PostmanAuthAttribute{{Key: "token", Value: "{{authToken}}", Type: "string"}},
+		}
+	case models.AuthSchemeAPIKey:
+		header := auth.HeaderName
+		if header == "" {
+			header = "X-API-Key"
+		}
+		return &models.PostmanAuth{
+			Type: "apikey",
+			APIKey: []models.PostmanAuthAttribute{
+				{Key: "key", Value: header, Type: "string"},
+				{Key: "value", Value: "{{apiKey}}", Type: "string"},
+				{Key: "in", Value: "header", Type: "string"},
+			},
+		}
+	case models.AuthSchemeBasic:
+		return &models.PostmanAuth{
+			Type: "basic",
+			Basic: []models.PostmanAuthAttribute{
+				{Key: "username", Value: "{{username}}", Type: "string"},
+				{Key: "password", Value: "{{password}}", Type: "string"},
+			},
+		}
+	}
+	return nil
+}
+
+// withAuthChangeNote prefixes the description with the PR's auth change so reviewers of the
+// collection notice security-relevant updates
+func withAuthChangeNote(description string, auth *models.RouteAuth) string {
+	if auth == nil || !auth.Changed {
+		return description
+	}
+	note := auth.Note
+	if note == "" {
+		note = fmt.Sprintf("authentication is now %s", auth.Scheme)
+	}
+	return fmt.Sprintf("**Auth change:** %s\n\n%s", note, description)
+}
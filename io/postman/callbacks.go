@@ -0,0 +1,100 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// applyCallbacks documents outbound webhooks in the callbacks folder, adding new ones and,
+// when replace is set, overwriting existing items of the same name
+func (c *Client) applyCallbacks(collection *models.PostmanCollection, analysis *models.AnalysisResponse, update *models.PostmanUpdate, replace bool) {
+	if len(analysis.Callbacks) == 0 {
+		return
+	}
+
+	folder := c.callbacksFolder(collection)
+	for _, callback := range analysis.Callbacks {
+		item := c.convertCallbackToPostmanItem(callback, analysis)
+
+		existing := -1
+		for i, it := range folder.Items {
+			if it.Name == item.Name {
+				existing = i
+				break
+			}
+		}
+
+		switch {
+		case existing < 0:
+			folder.Items = append(folder.Items, item)
+			update.ItemsAdded++
+		case replace:
+			folder.Items[existing] = item
+			update.ItemsModified++
+		}
+	}
+}
+
+// callbacksFolder returns the top-level callbacks folder, creating it if needed
+func (c *Client) callbacksFolder(collection *models.PostmanCollection) *models.PostmanItem {
+	name := c.config.CallbacksFolder
+	if name == "" {
+		name = "Webhooks"
+	}
+
+	return ensureFolder(&collection.Items, name, "Outbound webhooks sent by the API to subscriber URLs. Set {{callbackUrl}} to a request bin to try them.")
+}
+
+// convertCallbackToPostmanItem renders a callback as the request the API sends to subscribers
+func (c *Client) convertCallbackToPostmanItem(callback models.Callback, analysis *models.AnalysisResponse) models.PostmanItem {
+	method := strings.ToUpper(callback.Method)
+	if method == "" {
+		method = "POST"
+	}
+
+	headers := []models.PostmanHeader{{Key: "Content-Type", Value: "application/json", Type: "text"}}
+	for _, header := range callback.Headers {
+		headers = append(headers, models.PostmanHeader{
+			Key:         header.Name,
+			Value:       fmt.Sprintf("%v", header.Example),
+			Type:        "text",
+			Description: header.Description,
+		})
+	}
+
+	var body *models.PostmanBody
+	if len(callback.Payload) > 0 {
+		payloadJSON, _ := json.MarshalIndent(callback.Payload, "", "  ")
+		body = &models.PostmanBody{
+			Mode:    "raw",
+			Raw:     string(payloadJSON),
+			Options: map[string]any{"raw": map[string]any{"language": "json"}},
+		}
+	}
+
+	description := callback.Description
+	if callback.URL != "" {
+		description += fmt.Sprintf("\n\nSent to: `%s`", callback.URL)
+	}
+	if callback.TriggeredBy != "" {
+		description += fmt.Sprintf("\n\nRegistered via: `%s`", callback.TriggeredBy)
+	}
+
+	return models.PostmanItem{
+		Name:        fmt.Sprintf("%s %s", method, callback.Name),
+		Description: withProvenance(description, analysis),
+		Request: &models.PostmanRequest{
+			Method: method,
+			Header: headers,
+			Body:   body,
+			URL: models.PostmanURL{
+				Raw:  "{{callbackUrl}}",
+				Host: []string{"{{callbackUrl}}"},
+			},
+			Description: description,
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package postman
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const (
+	changelogStart  = "<!-- pr-documentator:changelog:start -->"
+	changelogEnd    = "<!-- pr-documentator:changelog:end -->"
+	changelogHeader = "### Automated documentation updates"
+)
+
+// updateChangelog records an update in a bounded changelog block at the end of the collection description
+func updateChangelog(description string, analysis *models.AnalysisResponse, update *models.PostmanUpdate, maxEntries int) string {
+	before, entries := splitChangelog(description)
+
+	source := "manual analysis"
+	if analysis != nil && analysis.PRNumber > 0 {
+		source = fmt.Sprintf("%s PR#%d", analysis.Repository, analysis.PRNumber)
+	}
+	entry := fmt.Sprintf("- %s %s: %d added, %d modified, %d deleted",
+		update.UpdatedAt, strings.TrimSpace(source), update.ItemsAdded, update.ItemsModified, update.ItemsDeleted)
+
+	// Newest entries first
+	entries = append([]string{entry}, entries...)
+	if maxEntries > 0 && len(entries) > maxEntries {
+		entries = entries[:maxEntries]
+	}
+
+	var b strings.Builder
+	if before != "" {
+		b.WriteString(before)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(changelogStart)
+	b.WriteString("\n")
+	b.WriteString(changelogHeader)
+	b.WriteString("\n")
+	b.WriteString(strings.Join(entries, "\n"))
+	b.WriteString("\n")
+	b.WriteString(changelogEnd)
+
+	return b.String()
+}
+
+// splitChangelog returns the description without the changelog block and the existing entries
+func splitChangelog(description string) (string, []string) {
+	start := strings.Index(description, changelogStart)
+	end := strings.Index(description, changelogEnd)
+	if start < 0 || end < start {
+		return strings.TrimSpace(description), nil
+	}
+
+	block := description[start+len(changelogStart) : end]
+	rest := strings.TrimSpace(description[:start] + description[end+len(changelogEnd):])
+
+	var entries []string
+	for _, line := range strings.Split(block, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "- ") {
+			entries = append(entries, strings.TrimSpace(line))
+		}
+	}
+
+	return rest, entries
+}
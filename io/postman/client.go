@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/sony/gobreaker"
@@ -14,7 +18,10 @@ import (
 	"github.com/igorsal/pr-documentator/internal/config"
 	"github.com/igorsal/pr-documentator/internal/interfaces"
 	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/doctemplate"
 	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+	"github.com/igorsal/pr-documentator/pkg/faker"
+	"github.com/igorsal/pr-documentator/pkg/httpclient"
 )
 
 type Client struct {
@@ -23,13 +30,32 @@ type Client struct {
 	logger         interfaces.Logger
 	circuitBreaker interfaces.CircuitBreaker
 	metrics        interfaces.MetricsCollector
+	nameTemplate   *template.Template
+	descTemplate   *template.Template // nil keeps Claude's description as is
+	rateLimit      *rateLimitTracker
+	audit          interfaces.AuditLogger
+	faker          *faker.Faker // nil unless FakeExamples is enabled
+	sync           *syncTracker
+	writes         *writeCoalescer
+	logBodyBytes   int // set by SetBodyLogging
+
+	apiKey atomic.Value // string; replaced by SetAPIKey when the secret manager rotates it
 }
 
 // NewClient creates a new Postman API client with circuit breaker
 func NewClient(cfg config.PostmanConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *Client {
 	// Configure HTTP client
-	client := &http.Client{
-		Timeout: cfg.Timeout,
+	if cfg.TLS.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is DISABLED for Postman API calls, do not use in production", "service", "postman")
+	}
+	client, err := httpclient.New(httpclient.Options{
+		Timeout:            cfg.Timeout,
+		CABundleFile:       cfg.TLS.CABundleFile,
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+	})
+	if err != nil {
+		logger.Error("Failed to configure custom TLS for Postman API, using default HTTP client", err)
+		client = &http.Client{Timeout: cfg.Timeout}
 	}
 
 	// Configure circuit breaker
@@ -53,13 +79,36 @@ func NewClient(cfg config.PostmanConfig, logger interfaces.Logger, metrics inter
 	// Wrap circuit breaker
 	cbWrapper := &postmanCircuitBreakerWrapper{cb: cb}
 
-	return &Client{
+	// Item name template is validated at config load, fall back to the default if unset
+	nameTemplate := cfg.ItemNameTemplate
+	if nameTemplate == "" {
+		nameTemplate = config.DefaultItemNameTemplate
+	}
+
+	// The description template is validated at config load as well
+	var descTemplate *template.Template
+	if cfg.DescriptionFormat == config.DescriptionFormatTemplate {
+		var err error
+		if descTemplate, err = doctemplate.Load(cfg.DescriptionTemplate); err != nil {
+			logger.Error("Failed to load Postman description template, using plain descriptions", err)
+		}
+	}
+
+	c := &Client{
 		httpClient:     client,
 		config:         cfg,
 		logger:         logger,
 		circuitBreaker: cbWrapper,
 		metrics:        metrics,
+		nameTemplate:   template.Must(template.New("item_name").Parse(nameTemplate)),
+		descTemplate:   descTemplate,
+		rateLimit:      &rateLimitTracker{},
+		faker:          fakerFor(cfg),
+		sync:           newSyncTracker(),
 	}
+	c.writes = newWriteCoalescer(cfg.WriteBatchWindow, cfg.WriteLockTimeout, c.writeBatch, c.observeWriteLock)
+	c.apiKey.Store(cfg.APIKey)
+	return c
 }
 
 // postmanCircuitBreakerWrapper implements interfaces.CircuitBreaker
@@ -79,8 +128,22 @@ func (w *postmanCircuitBreakerWrapper) State() string {
 	return w.cb.State().String()
 }
 
-// GetCollection retrieves a Postman collection
-func (c *Client) GetCollection(ctx context.Context) (*models.PostmanCollection, error) {
+// CircuitBreaker exposes the client's circuit breaker for health reporting
+func (c *Client) CircuitBreaker() interfaces.CircuitBreaker {
+	return c.circuitBreaker
+}
+
+// GetCollection retrieves a Postman collection. An empty collectionID uses the configured collection.
+func (c *Client) GetCollection(ctx context.Context, collectionID string) (*models.PostmanCollection, error) {
+	versioned, err := c.getVersionedCollection(ctx, c.resolveCollectionID(collectionID))
+	if err != nil {
+		return nil, err
+	}
+	return versioned.collection, nil
+}
+
+// getVersionedCollection retrieves a collection together with the version it was read at
+func (c *Client) getVersionedCollection(ctx context.Context, collectionID string) (*versionedCollection, error) {
 	startTime := time.Now()
 	labels := map[string]string{
 		"service":   "postman",
@@ -88,7 +151,7 @@ func (c *Client) GetCollection(ctx context.Context) (*models.PostmanCollection,
 	}
 
 	result, err := c.circuitBreaker.Execute(func() (any, error) {
-		return c.executeGetCollection(ctx)
+		return c.executeGetCollection(ctx, collectionID)
 	})
 
 	duration := time.Since(startTime).Seconds()
@@ -102,21 +165,27 @@ func (c *Client) GetCollection(ctx context.Context) (*models.PostmanCollection,
 
 	labels["status"] = "success"
 	c.metrics.IncrementCounter("postman_requests_total", labels)
-	return result.(*models.PostmanCollection), nil
+	return result.(*versionedCollection), nil
 }
 
-func (c *Client) executeGetCollection(ctx context.Context) (*models.PostmanCollection, error) {
-	url := fmt.Sprintf("%s/collections/%s", c.config.BaseURL, c.config.CollectionID)
+// collectionURL builds the API URL of a collection, escaping the ID so it cannot address other paths
+func collectionURL(baseURL, collectionID string) string {
+	return fmt.Sprintf("%s/collections/%s", baseURL, url.PathEscape(collectionID))
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, pkgerrors.NewExternalError("postman", "failed to create request").WithCause(err)
-	}
+func (c *Client) executeGetCollection(ctx context.Context, collectionID string) (*versionedCollection, error) {
+	endpoint := collectionURL(c.config.BaseURL, collectionID)
 
-	req.Header.Set("X-API-Key", c.config.APIKey)
-	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doWithRateLimit(ctx, "get_collection", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, pkgerrors.NewExternalError("postman", "failed to create request").WithCause(err)
+		}
 
-	resp, err := c.httpClient.Do(req)
+		req.Header.Set("X-API-Key", c.currentAPIKey())
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, pkgerrors.NewExternalError("postman", err.Error()).WithCause(err)
 	}
@@ -128,16 +197,19 @@ func (c *Client) executeGetCollection(ctx context.Context) (*models.PostmanColle
 	}
 
 	if resp.StatusCode >= 400 {
+		var httpErr *pkgerrors.AppError
 		switch resp.StatusCode {
 		case 401:
-			return nil, pkgerrors.NewUnauthorizedError("Invalid Postman API key")
+			httpErr = pkgerrors.NewUnauthorizedError("Invalid Postman API key").WithCode(pkgerrors.CodePostmanUnauthorized)
 		case 404:
-			return nil, pkgerrors.NewNotFoundError("Collection not found")
+			httpErr = pkgerrors.NewNotFoundError("Collection not found").WithCode(pkgerrors.CodePostmanNotFound)
 		case 429:
-			return nil, pkgerrors.NewRateLimitError("postman")
+			httpErr = pkgerrors.NewRateLimitError("postman")
 		default:
-			return nil, pkgerrors.NewExternalError("postman", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)))
+			httpErr = pkgerrors.NewExternalError("postman", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)))
 		}
+		c.logFailedCall("get_collection", httpErr, resp.StatusCode, nil, respBody)
+		return nil, httpErr
 	}
 
 	var collectionResp models.PostmanCollectionResponse
@@ -145,50 +217,96 @@ func (c *Client) executeGetCollection(ctx context.Context) (*models.PostmanColle
 		return nil, pkgerrors.NewExternalError("postman", "failed to parse response").WithCause(err)
 	}
 
-	return &collectionResp.Collection, nil
+	return &versionedCollection{
+		collection: &collectionResp.Collection,
+		version:    collectionVersion{etag: resp.Header.Get("ETag"), updatedAt: collectionResp.Collection.Info.UpdatedAt},
+	}, nil
 }
 
-// UpdateCollection updates a Postman collection with new API routes
-func (c *Client) UpdateCollection(ctx context.Context, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error) {
-	c.logger.Info("Starting Postman collection update", "collection_id", c.config.CollectionID)
+// UpdateCollection updates a Postman collection with new API routes. An empty collectionID uses the configured collection.
+// Writes are coalesced per collection, see writeCoalescer. Dry runs (POSTMAN_DRY_RUN or models.WithDryRun) compute the
+// changes and return them as a unified diff preview without writing.
+func (c *Client) UpdateCollection(ctx context.Context, collectionID string, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error) {
+	collectionID = c.resolveCollectionID(collectionID)
+	dryRun := c.config.DryRun || models.IsDryRun(ctx)
+	c.logger.Info("Starting Postman collection update", "collection_id", collectionID, "dry_run", dryRun)
 
-	// First, get the current collection
-	collection, err := c.GetCollection(ctx)
+	if dryRun {
+		return c.previewUpdate(ctx, collectionID, analysisResp)
+	}
+	return c.writes.submit(ctx, collectionID, analysisResp)
+}
+
+// previewUpdate applies analysisResp to a copy of the collection and returns the changes without writing
+func (c *Client) previewUpdate(ctx context.Context, collectionID string, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error) {
+	collection, err := c.GetCollection(ctx, collectionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get collection: %w", err)
 	}
-
-	// Update the collection with new routes
-	updated, err := c.updateCollectionWithRoutes(collection, analysisResp)
+	before, err := cloneCollection(collection)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update collection: %w", err)
+		return nil, err
 	}
 
-	// Send the updated collection back to Postman
-	if err := c.putCollection(ctx, collection); err != nil {
-		return nil, fmt.Errorf("failed to save updated collection: %w", err)
+	updated, err := c.applyAnalysis(collection, collectionID, analysisResp)
+	if err != nil {
+		return nil, err
 	}
 
-	c.logger.Info("Successfully updated Postman collection",
-		"collection_id", c.config.CollectionID,
+	updated.Status = models.PostmanStatusDryRun
+	updated.Preview = buildPreview(before, collection)
+	c.logger.Info("Dry run, not writing Postman collection",
+		"collection_id", collectionID,
 		"items_added", updated.ItemsAdded,
 		"items_modified", updated.ItemsModified,
 		"items_deleted", updated.ItemsDeleted,
 	)
+	return updated, nil
+}
 
+// applyAnalysis updates the collection in memory with the routes and changelog entry of one analysis
+func (c *Client) applyAnalysis(collection *models.PostmanCollection, collectionID string, analysisResp *models.AnalysisResponse) (*models.PostmanUpdate, error) {
+	updated, err := c.updateCollectionWithRoutes(collection, analysisResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update collection: %w", err)
+	}
+	updated.CollectionID = collectionID
+
+	if added := ensureCollectionVariables(collection); len(added) > 0 {
+		c.logger.Info("Added collection variables referenced by requests", "collection_id", collectionID, "variables", added)
+		updated.VariablesAdded = added
+	}
+
+	if c.config.ChangelogEnabled {
+		collection.Info.Description = updateChangelog(collection.Info.Description, analysisResp, updated, c.config.ChangelogMaxEntries)
+	}
 	return updated, nil
 }
 
-func (c *Client) putCollection(ctx context.Context, collection *models.PostmanCollection) error {
+// resolveCollectionID falls back to the configured collection when no override is given
+func (c *Client) resolveCollectionID(collectionID string) string {
+	if collectionID == "" {
+		return c.config.CollectionID
+	}
+	return collectionID
+}
+
+// putCollection saves the collection. With a known ETag the write is conditional and fails with
+// errWriteConflict when the collection changed since it was read.
+func (c *Client) putCollection(ctx context.Context, collectionID string, collection *models.PostmanCollection, version collectionVersion) error {
 	startTime := time.Now()
 	labels := map[string]string{
 		"service":   "postman",
 		"operation": "put_collection",
 	}
 
-	_, err := c.circuitBreaker.Execute(func() (any, error) {
-		return nil, c.executePutCollection(ctx, collection)
+	// A conflict is a healthy response, so it is returned outside the breaker instead of counting as a failure
+	result, err := c.circuitBreaker.Execute(func() (any, error) {
+		return c.executePutCollection(ctx, collectionID, collection, version)
 	})
+	if err == nil && result.(bool) {
+		err = errWriteConflict
+	}
 
 	duration := time.Since(startTime).Seconds()
 	c.metrics.RecordDuration("postman_request_duration_seconds", duration, labels)
@@ -204,85 +322,189 @@ func (c *Client) putCollection(ctx context.Context, collection *models.PostmanCo
 	return nil
 }
 
-func (c *Client) executePutCollection(ctx context.Context, collection *models.PostmanCollection) error {
+// executePutCollection reports true when Postman rejected a conditional write as stale
+func (c *Client) executePutCollection(ctx context.Context, collectionID string, collection *models.PostmanCollection, version collectionVersion) (bool, error) {
 	updateReq := models.PostmanUpdateRequest{
 		Collection: *collection,
 	}
 
 	body, err := json.Marshal(updateReq)
 	if err != nil {
-		return pkgerrors.NewExternalError("postman", "failed to marshal request").WithCause(err)
-	}
-
-	url := fmt.Sprintf("%s/collections/%s", c.config.BaseURL, c.config.CollectionID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewBuffer(body))
-	if err != nil {
-		return pkgerrors.NewExternalError("postman", "failed to create request").WithCause(err)
+		return false, pkgerrors.NewExternalError("postman", "failed to marshal request").WithCause(err)
 	}
 
-	req.Header.Set("X-API-Key", c.config.APIKey)
-	req.Header.Set("Content-Type", "application/json")
+	endpoint := collectionURL(c.config.BaseURL, collectionID)
+	resp, err := c.doWithRateLimit(ctx, "put_collection", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, pkgerrors.NewExternalError("postman", "failed to create request").WithCause(err)
+		}
 
-	resp, err := c.httpClient.Do(req)
+		req.Header.Set("X-API-Key", c.currentAPIKey())
+		req.Header.Set("Content-Type", "application/json")
+		if version.etag != "" {
+			req.Header.Set("If-Match", version.etag)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return pkgerrors.NewExternalError("postman", err.Error()).WithCause(err)
+		c.logFailedCall("put_collection", err, 0, body, nil)
+		return false, pkgerrors.NewExternalError("postman", err.Error()).WithCause(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		respBody, _ := io.ReadAll(resp.Body)
+		var httpErr *pkgerrors.AppError
 		switch resp.StatusCode {
 		case 401:
-			return pkgerrors.NewUnauthorizedError("Invalid Postman API key")
+			httpErr = pkgerrors.NewUnauthorizedError("Invalid Postman API key").WithCode(pkgerrors.CodePostmanUnauthorized)
 		case 404:
-			return pkgerrors.NewNotFoundError("Collection not found")
+			httpErr = pkgerrors.NewNotFoundError("Collection not found").WithCode(pkgerrors.CodePostmanNotFound)
+		case 409, 412:
+			return true, nil
 		case 429:
-			return pkgerrors.NewRateLimitError("postman")
+			httpErr = pkgerrors.NewRateLimitError("postman")
 		default:
-			return pkgerrors.NewExternalError("postman", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)))
+			httpErr = pkgerrors.NewExternalError("postman", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)))
 		}
+		c.logFailedCall("put_collection", httpErr, resp.StatusCode, body, respBody)
+		return false, httpErr
 	}
 
-	return nil
+	return false, nil
 }
 
 func (c *Client) updateCollectionWithRoutes(collection *models.PostmanCollection, analysis *models.AnalysisResponse) (*models.PostmanUpdate, error) {
 	update := &models.PostmanUpdate{
 		CollectionID: c.config.CollectionID,
 		Status:       "success",
+		UpdateMode:   c.config.UpdateMode,
 		UpdatedAt:    time.Now().Format(time.RFC3339),
 	}
 
+	switch c.config.UpdateMode {
+	case config.UpdateModeAdditive:
+		c.applyAdditiveUpdate(collection, analysis, update)
+	case config.UpdateModeAnnotate:
+		c.applyAnnotateUpdate(collection, analysis, update)
+	default:
+		c.applyFullUpdate(collection, analysis, update)
+	}
+
+	return update, nil
+}
+
+// applyFullUpdate adds new routes, replaces modified ones and marks deleted routes as deprecated
+func (c *Client) applyFullUpdate(collection *models.PostmanCollection, analysis *models.AnalysisResponse, update *models.PostmanUpdate) {
 	// Add new routes
 	for _, route := range analysis.NewRoutes {
-		item := c.convertRouteToPostmanItem(route)
-		collection.Items = append(collection.Items, item)
-		update.ItemsAdded++
+		c.isolateRoute(route, update, func() error {
+			item, err := c.buildItem(route, analysis)
+			if err != nil {
+				return err
+			}
+			// A route removed by an earlier PR and now re-added
+			if c.reviveItem(collection, route, item) {
+				update.ItemsModified++
+				return nil
+			}
+			c.addItem(collection, route, item)
+			update.ItemsAdded++
+			return nil
+		})
 	}
 
 	// Update modified routes
 	for _, route := range analysis.ModifiedRoutes {
-		if c.updateExistingItem(collection, route) {
-			update.ItemsModified++
-		} else {
-			// If route not found, add as new
-			item := c.convertRouteToPostmanItem(route)
-			collection.Items = append(collection.Items, item)
-			update.ItemsAdded++
-		}
+		c.isolateRoute(route, update, func() error {
+			item, err := c.buildItem(route, analysis)
+			if err != nil {
+				return err
+			}
+			if c.reviveItem(collection, route, item) || c.updateExistingItem(collection, route, item) {
+				update.ItemsModified++
+			} else {
+				// If route not found, add as new
+				c.addItem(collection, route, item)
+				update.ItemsAdded++
+			}
+			return nil
+		})
 	}
 
-	// Mark deleted routes (we don't actually delete, just mark as deprecated)
+	// Delete removed routes when configured, otherwise mark them as deprecated
 	for _, route := range analysis.DeletedRoutes {
-		if c.markItemAsDeprecated(collection, route) {
-			update.ItemsModified++
+		c.isolateRoute(route, update, func() error {
+			if route.Removed && c.config.DeleteRemoved {
+				if c.removeItem(collection, route) {
+					update.ItemsDeleted++
+				}
+				return nil
+			}
+			if c.markItemAsDeprecated(collection, route) {
+				update.ItemsModified++
+			}
+			return nil
+		})
+	}
+
+	c.applyCallbacks(collection, analysis, update, true)
+}
+
+// applyAdditiveUpdate only adds routes that aren't documented yet and never touches existing items
+func (c *Client) applyAdditiveUpdate(collection *models.PostmanCollection, analysis *models.AnalysisResponse, update *models.PostmanUpdate) {
+	for _, routes := range [][]models.APIRoute{analysis.NewRoutes, analysis.ModifiedRoutes} {
+		for _, route := range routes {
+			if _, ok := c.findItem(collection, route); ok {
+				continue
+			}
+			c.isolateRoute(route, update, func() error {
+				item, err := c.buildItem(route, analysis)
+				if err != nil {
+					return err
+				}
+				c.addItem(collection, route, item)
+				update.ItemsAdded++
+				return nil
+			})
 		}
 	}
 
-	return update, nil
+	c.applyCallbacks(collection, analysis, update, false)
 }
 
-func (c *Client) convertRouteToPostmanItem(route models.APIRoute) models.PostmanItem {
+// applyAnnotateUpdate only updates descriptions and auth of existing items and never changes collection structure
+func (c *Client) applyAnnotateUpdate(collection *models.PostmanCollection, analysis *models.AnalysisResponse, update *models.PostmanUpdate) {
+	for _, route := range analysis.ModifiedRoutes {
+		loc, ok := c.findItem(collection, route)
+		if !ok || route.Description == "" {
+			continue
+		}
+		loc.item().Description = withProvenance(withAuthChangeNote(c.itemDescription(route), route.Auth), analysis)
+		if auth := buildAuth(route.Auth); auth != nil && loc.item().Request != nil {
+			loc.item().Request.Auth = auth
+		}
+		update.ItemsModified++
+	}
+
+	for _, route := range analysis.DeletedRoutes {
+		loc, ok := c.findItem(collection, route)
+		if !ok || strings.HasPrefix(loc.item().Description, "[DEPRECATED]") {
+			continue
+		}
+		loc.item().Description = strings.TrimSpace("[DEPRECATED] " + loc.item().Description)
+		update.ItemsModified++
+	}
+}
+
+// convertRouteToPostmanItem renders route as a Postman request item, failing on routes that
+// can't be documented, e.g. without a method or path or with unserializable bodies
+func (c *Client) convertRouteToPostmanItem(route models.APIRoute, analysis *models.AnalysisResponse) (models.PostmanItem, error) {
+	if route.Method == "" || route.Path == "" {
+		return models.PostmanItem{}, fmt.Errorf("route is missing its method or path")
+	}
+
 	// Convert path to Postman URL format
 	pathSegments := []string{}
 	if route.Path != "" && route.Path != "/" {
@@ -318,21 +540,36 @@ func (c *Client) convertRouteToPostmanItem(route models.APIRoute) models.Postman
 	}
 
 	// Add parameters as query params or path variables
+	var pathVariables []models.PostmanVariable
 	for _, param := range route.Parameters {
-		if param.In == "query" {
+		switch param.In {
+		case "query":
 			queryParams = append(queryParams, models.PostmanQueryParam{
 				Key:         param.Name,
-				Value:       fmt.Sprintf("%v", param.Example),
-				Description: param.Description,
+				Value:       c.exampleValue(param),
+				Description: param.DescriptionWithConstraints(),
 				Disabled:    !param.Required,
 			})
+		case "path":
+			pathVariables = append(pathVariables, models.PostmanVariable{
+				Key:         param.Name,
+				Value:       c.exampleValue(param),
+				Type:        "string",
+				Description: param.DescriptionWithConstraints(),
+			})
 		}
 	}
 
+	// Document standard pagination params for list endpoints
+	queryParams = withPaginationParams(route.Pagination, queryParams)
+
 	// Create request body
 	var body *models.PostmanBody
 	if route.RequestBody != nil && len(route.RequestBody) > 0 {
-		bodyJSON, _ := json.MarshalIndent(route.RequestBody, "", "  ")
+		bodyJSON, err := json.MarshalIndent(route.RequestBody, "", "  ")
+		if err != nil {
+			return models.PostmanItem{}, fmt.Errorf("invalid request body: %w", err)
+		}
 		body = &models.PostmanBody{
 			Mode: "raw",
 			Raw:  string(bodyJSON),
@@ -344,81 +581,116 @@ func (c *Client) convertRouteToPostmanItem(route models.APIRoute) models.Postman
 		}
 	}
 
-	// Create example response
-	var responses []models.PostmanResponse
-	if route.Response != nil && len(route.Response) > 0 {
-		respJSON, _ := json.MarshalIndent(route.Response, "", "  ")
-		responses = append(responses, models.PostmanResponse{
-			Name:   "Success Response",
-			Status: "OK",
-			Code:   200,
-			Header: []models.PostmanHeader{
-				{
-					Key:   "Content-Type",
-					Value: "application/json",
-				},
-			},
-			Body: string(respJSON),
-		})
+	// Create an example response per documented status code
+	responses, err := buildResponseExamples(route)
+	if err != nil {
+		return models.PostmanItem{}, err
 	}
+	responses = withRateLimitHeaders(responses, route.RateLimit)
+	responses = c.limitResponseExamples(route, responses)
 
 	return models.PostmanItem{
-		Name:        fmt.Sprintf("%s %s", route.Method, route.Path),
-		Description: route.Description,
+		Name:        c.itemName(route),
+		Description: withProvenance(withAuthChangeNote(c.itemDescription(route), route.Auth), analysis),
 		Request: &models.PostmanRequest{
 			Method: route.Method,
 			Header: headers,
 			Body:   body,
+			Auth:   buildAuth(route.Auth),
 			URL: models.PostmanURL{
-				Raw:   fmt.Sprintf("{{baseUrl}}%s", route.Path),
-				Host:  []string{"{{baseUrl}}"},
-				Path:  pathSegments,
-				Query: queryParams,
+				Raw:      fmt.Sprintf("{{baseUrl}}%s", route.Path),
+				Host:     []string{"{{baseUrl}}"},
+				Path:     pathSegments,
+				Query:    queryParams,
+				Variable: pathVariables,
 			},
 			Description: route.Description,
 		},
 		Response: responses,
-	}
+	}, nil
 }
 
-func (c *Client) updateExistingItem(collection *models.PostmanCollection, route models.APIRoute) bool {
-	routeName := fmt.Sprintf("%s %s", route.Method, route.Path)
+// itemName renders the configured naming template for a route, falling back to "<METHOD> <path>"
+func (c *Client) itemName(route models.APIRoute) string {
+	var buf strings.Builder
+	if err := c.nameTemplate.Execute(&buf, route); err != nil {
+		c.logger.Warn("Failed to render item name template", "error", err, "method", route.Method, "path", route.Path)
+		return fmt.Sprintf("%s %s", route.Method, route.Path)
+	}
+	return buf.String()
+}
 
-	for i, item := range collection.Items {
-		if item.Name == routeName || (item.Request != nil &&
-			item.Request.Method == route.Method &&
-			item.Request.URL.Raw == fmt.Sprintf("{{baseUrl}}%s", route.Path)) {
+// itemDescription renders the configured description template for a route, falling back to
+// Claude's description
+func (c *Client) itemDescription(route models.APIRoute) string {
+	if c.descTemplate == nil {
+		return route.Description
+	}
+	description, err := doctemplate.Render(c.descTemplate, route)
+	if err != nil {
+		c.logger.Warn("Failed to render item description template", "error", err, "method", route.Method, "path", route.Path)
+		return route.Description
+	}
+	return description
+}
 
-			// Update the existing item
-			collection.Items[i] = c.convertRouteToPostmanItem(route)
-			return true
-		}
+// updateExistingItem replaces the item documenting route with item in place, keeping its folder
+func (c *Client) updateExistingItem(collection *models.PostmanCollection, route models.APIRoute, item models.PostmanItem) bool {
+	loc, ok := c.findItem(collection, route)
+	if !ok {
+		return false
 	}
-	return false
+
+	*loc.item() = item
+	return true
 }
 
 func (c *Client) markItemAsDeprecated(collection *models.PostmanCollection, route models.APIRoute) bool {
-	routeName := fmt.Sprintf("%s %s", route.Method, route.Path)
+	loc, ok := c.findItem(collection, route)
+	if !ok {
+		return false
+	}
 
-	for i, item := range collection.Items {
-		if item.Name == routeName || (item.Request != nil &&
-			item.Request.Method == route.Method &&
-			item.Request.URL.Raw == fmt.Sprintf("{{baseUrl}}%s", route.Path)) {
+	if c.config.DeprecationMode == config.DeprecationModeFolder {
+		c.moveItemToDeprecatedFolder(collection, loc)
+		return true
+	}
 
-			// Mark as deprecated by adding to description
-			if collection.Items[i].Description == "" {
-				collection.Items[i].Description = "[DEPRECATED] This endpoint is deprecated."
-			} else {
-				collection.Items[i].Description = "[DEPRECATED] " + collection.Items[i].Description
-			}
+	// Mark as deprecated by adding to description
+	item := loc.item()
+	if item.Description == "" {
+		item.Description = "[DEPRECATED] This endpoint is deprecated."
+	} else {
+		item.Description = "[DEPRECATED] " + item.Description
+	}
 
-			// Also update the name
-			if collection.Items[i].Name != "" && collection.Items[i].Name[:12] != "[DEPRECATED]" {
-				collection.Items[i].Name = "[DEPRECATED] " + collection.Items[i].Name
-			}
+	// Also update the name
+	if item.Name != "" && !strings.HasPrefix(item.Name, "[DEPRECATED]") {
+		item.Name = "[DEPRECATED] " + item.Name
+	}
 
-			return true
-		}
+	return true
+}
+
+// removeItem deletes the item documenting route, reporting whether one was found
+func (c *Client) removeItem(collection *models.PostmanCollection, route models.APIRoute) bool {
+	loc, ok := c.findItem(collection, route)
+	if !ok {
+		return false
 	}
-	return false
+	loc.remove()
+	return true
+}
+
+// moveItemToDeprecatedFolder moves the item at loc into the top-level deprecated folder, creating it if needed
+func (c *Client) moveItemToDeprecatedFolder(collection *models.PostmanCollection, loc itemLocation) {
+	item := loc.remove()
+
+	folderName := c.config.DeprecatedFolder
+	if folderName == "" {
+		folderName = "_deprecated"
+	}
+
+	folder := ensureFolder(&collection.Items, folderName, "Endpoints removed from the codebase, kept for reference.")
+	folder.Items = append(folder.Items, item)
 }
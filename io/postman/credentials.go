@@ -0,0 +1,12 @@
+package postman
+
+// SetAPIKey replaces the Postman API key used by subsequent requests, e.g. after the secret
+// manager rotated it
+func (c *Client) SetAPIKey(key string) {
+	c.apiKey.Store(key)
+}
+
+func (c *Client) currentAPIKey() string {
+	key, _ := c.apiKey.Load().(string)
+	return key
+}
@@ -0,0 +1,33 @@
+package postman
+
+import (
+	"fmt"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/faker"
+)
+
+func fakerFor(cfg config.PostmanConfig) *faker.Faker {
+	if !cfg.FakeExamples {
+		return nil
+	}
+	return faker.New(int64(cfg.FakerSeed))
+}
+
+// exampleValue returns the parameter's example, generating a realistic one when Claude gave none
+func (c *Client) exampleValue(param models.Parameter) string {
+	if param.Example != nil && fmt.Sprintf("%v", param.Example) != "" {
+		return fmt.Sprintf("%v", param.Example)
+	}
+	if param.Default != nil {
+		return fmt.Sprintf("%v", param.Default)
+	}
+	if len(param.Enum) > 0 {
+		return fmt.Sprintf("%v", param.Enum[0])
+	}
+	if c.faker == nil {
+		return ""
+	}
+	return c.faker.Value(param.Name, param.Type, param.Format)
+}
@@ -0,0 +1,113 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/pathutil"
+)
+
+// versionSegment matches path segments like v1 or v2, which never name a prefix folder
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// itemLocation addresses an item inside a top-level or folder item list
+type itemLocation struct {
+	items *[]models.PostmanItem
+	index int
+}
+
+func (l itemLocation) item() *models.PostmanItem {
+	return &(*l.items)[l.index]
+}
+
+// remove deletes the item from its list and returns it
+func (l itemLocation) remove() models.PostmanItem {
+	item := (*l.items)[l.index]
+	*l.items = append((*l.items)[:l.index], (*l.items)[l.index+1:]...)
+	return item
+}
+
+// findItem locates the item documenting route anywhere in the collection's folders, skipping
+// the deprecated and callbacks folders. Paths are compared after normalization so trailing
+// slashes and duplicate slashes don't create duplicates.
+func (c *Client) findItem(collection *models.PostmanCollection, route models.APIRoute) (itemLocation, bool) {
+	return c.findItemIn(&collection.Items, c.itemName(route), route)
+}
+
+func (c *Client) findItemIn(items *[]models.PostmanItem, routeName string, route models.APIRoute) (itemLocation, bool) {
+	for i := range *items {
+		item := &(*items)[i]
+		if item.Request == nil {
+			if item.Name == c.config.DeprecatedFolder || item.Name == c.config.CallbacksFolder {
+				continue
+			}
+			if loc, ok := c.findItemIn(&item.Items, routeName, route); ok {
+				return loc, true
+			}
+			continue
+		}
+
+		if item.Name == routeName ||
+			(strings.EqualFold(item.Request.Method, route.Method) &&
+				pathutil.Equal(item.Request.URL.Raw, route.Path, c.config.PathCaseSensitive)) {
+			return itemLocation{items: items, index: i}, true
+		}
+	}
+	return itemLocation{}, false
+}
+
+// addItem appends a new item to the collection, inside its version folder when grouping by
+// version and then inside the folder chosen by the folder strategy
+func (c *Client) addItem(collection *models.PostmanCollection, route models.APIRoute, item models.PostmanItem) {
+	items := &collection.Items
+
+	if c.config.GroupByVersion && route.Version != "" {
+		items = &ensureFolder(items, route.Version, fmt.Sprintf("API %s endpoints", route.Version)).Items
+	}
+	if name := c.folderName(route); name != "" {
+		items = &ensureFolder(items, name, "").Items
+	}
+
+	*items = append(*items, item)
+}
+
+// folderName returns the folder a new route belongs in under the configured strategy, or "" for none
+func (c *Client) folderName(route models.APIRoute) string {
+	switch c.config.FolderStrategy {
+	case config.FolderStrategyTag:
+		if len(route.Tags) > 0 {
+			return strings.TrimSpace(route.Tags[0])
+		}
+	case config.FolderStrategyPrefix:
+		return pathPrefix(route.Path)
+	}
+	return ""
+}
+
+// pathPrefix returns the first static path segment, ignoring "api", version and parameter segments
+func pathPrefix(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "", strings.EqualFold(segment, "api"), versionSegment.MatchString(segment),
+			strings.HasPrefix(segment, "{"), strings.HasPrefix(segment, ":"):
+			continue
+		}
+		return segment
+	}
+	return ""
+}
+
+// ensureFolder returns the folder named name in items, creating it if needed
+func ensureFolder(items *[]models.PostmanItem, name, description string) *models.PostmanItem {
+	for i := range *items {
+		if (*items)[i].Request == nil && (*items)[i].Name == name {
+			return &(*items)[i]
+		}
+	}
+
+	*items = append(*items, models.PostmanItem{Name: name, Description: description})
+	return &(*items)[len(*items)-1]
+}
@@ -0,0 +1,30 @@
+package postman
+
+import (
+	"fmt"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// isolateRoute applies one route's change to the collection. A route that fails, or panics, is
+// logged and counted in ItemsFailed instead of aborting the rest of the update.
+func (c *Client) isolateRoute(route models.APIRoute, update *models.PostmanUpdate, apply func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.recordRouteFailure(route, update, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if err := apply(); err != nil {
+		c.recordRouteFailure(route, update, err)
+	}
+}
+
+func (c *Client) recordRouteFailure(route models.APIRoute, update *models.PostmanUpdate, err error) {
+	c.logger.Error("Skipping route that could not be documented", err,
+		"method", route.Method,
+		"path", route.Path,
+	)
+	update.ItemsFailed++
+	update.Status = "partial"
+}
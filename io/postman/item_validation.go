@@ -0,0 +1,225 @@
+package postman
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// itemSchemaJSON is the part of the Postman v2.1 collection schema describing generated request items
+//
+//go:embed schema/item.schema.json
+var itemSchemaJSON []byte
+
+var itemSchema = mustParseSchema(itemSchemaJSON)
+
+func mustParseSchema(data []byte) map[string]any {
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		panic(fmt.Sprintf("invalid embedded Postman item schema: %v", err))
+	}
+	return schema
+}
+
+// buildItem converts route to a Postman item that is valid per the collection schema
+func (c *Client) buildItem(route models.APIRoute, analysis *models.AnalysisResponse) (models.PostmanItem, error) {
+	item, err := c.convertRouteToPostmanItem(route, analysis)
+	if err != nil {
+		return item, err
+	}
+	return c.validateItem(route, item)
+}
+
+// validateItem checks a generated item against the Postman item schema. Invalid items are
+// repaired where that is safe, e.g. dropping headers without a key; items that are still
+// invalid afterwards are rejected, so they never reach the collection.
+func (c *Client) validateItem(route models.APIRoute, item models.PostmanItem) (models.PostmanItem, error) {
+	if !c.config.ValidateItems {
+		return item, nil
+	}
+
+	violations, err := itemViolations(item)
+	if err != nil {
+		return item, err
+	}
+	if len(violations) == 0 {
+		return item, nil
+	}
+
+	c.logger.Warn("Generated Postman item violates the collection schema, repairing it",
+		"method", route.Method,
+		"path", route.Path,
+		"violations", violations,
+	)
+	repairItem(&item)
+
+	if violations, err = itemViolations(item); err != nil {
+		return item, err
+	}
+	if len(violations) > 0 {
+		return item, fmt.Errorf("item violates the Postman collection schema: %s", strings.Join(violations, "; "))
+	}
+	return item, nil
+}
+
+func itemViolations(item models.PostmanItem) ([]string, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode item: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode item: %w", err)
+	}
+
+	var violations []string
+	validateSchema(itemSchema, itemSchema, doc, "item", &violations)
+	return violations, nil
+}
+
+// repairItem fixes the schema violations that have an unambiguous correction
+func repairItem(item *models.PostmanItem) {
+	if item.Request == nil {
+		return
+	}
+	item.Request.Method = strings.ToUpper(strings.TrimSpace(item.Request.Method))
+	if strings.TrimSpace(item.Name) == "" {
+		item.Name = strings.TrimSpace(item.Request.Method + " " + item.Request.URL.Raw)
+	}
+	item.Request.Header = withoutBlankHeaders(item.Request.Header)
+
+	responses := item.Response[:0]
+	for _, resp := range item.Response {
+		if resp.Code < 100 || resp.Code > 599 {
+			continue
+		}
+		resp.Header = withoutBlankHeaders(resp.Header)
+		resp.OriginalRequest.Method = item.Request.Method
+		resp.OriginalRequest.Header = withoutBlankHeaders(resp.OriginalRequest.Header)
+		responses = append(responses, resp)
+	}
+	item.Response = responses
+}
+
+func withoutBlankHeaders(headers []models.PostmanHeader) []models.PostmanHeader {
+	kept := headers[:0]
+	for _, h := range headers {
+		if strings.TrimSpace(h.Key) != "" {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
+
+// validateSchema checks value against the JSON Schema keywords used by the embedded schema:
+// $ref (local definitions), type, required, properties, items, enum, minLength, minimum and maximum
+func validateSchema(root, schema map[string]any, value any, path string, violations *[]string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		definitions, _ := root["definitions"].(map[string]any)
+		if def, ok := definitions[name].(map[string]any); ok {
+			validateSchema(root, def, value, path, violations)
+		}
+		return
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %v, got %s", path, types, jsonType(value)))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && value != nil {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && float64(len(v)) < min {
+			*violations = append(*violations, fmt.Sprintf("%s: must not be empty", path))
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is below %v", path, v, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is above %v", path, v, max))
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					*violations = append(*violations, fmt.Sprintf("%s: missing %s", path, name))
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for name, propSchema := range properties {
+				if propValue, present := v[name]; present {
+					validateSchema(root, propSchema.(map[string]any), propValue, path+"."+name, violations)
+				}
+			}
+		}
+	case []any:
+		if itemsSchema, ok := schema["items"].(map[string]any); ok {
+			for i, elem := range v {
+				validateSchema(root, itemsSchema, elem, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+}
+
+func matchesType(types any, value any) bool {
+	switch t := types.(type) {
+	case string:
+		return typeMatches(t, value)
+	case []any:
+		for _, name := range t {
+			if typeMatches(name.(string), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeMatches(name string, value any) bool {
+	actual := jsonType(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,39 @@
+package postman
+
+import (
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// paginationParams are the standard query parameters documented for each pagination style
+var paginationParams = map[string][]models.PostmanQueryParam{
+	models.PaginationPage: {
+		{Key: "page", Value: "1", Description: "Page number, starting at 1", Disabled: true},
+		{Key: "limit", Value: "20", Description: "Maximum number of items per page", Disabled: true},
+	},
+	models.PaginationOffset: {
+		{Key: "offset", Value: "0", Description: "Number of items to skip", Disabled: true},
+		{Key: "limit", Value: "20", Description: "Maximum number of items to return", Disabled: true},
+	},
+	models.PaginationCursor: {
+		{Key: "cursor", Value: "", Description: "Opaque cursor from the previous page's response", Disabled: true},
+		{Key: "limit", Value: "20", Description: "Maximum number of items to return", Disabled: true},
+	},
+}
+
+// withPaginationParams adds the standard pagination query params for the route's style,
+// keeping any the analysis already documented
+func withPaginationParams(style string, params []models.PostmanQueryParam) []models.PostmanQueryParam {
+	for _, standard := range paginationParams[style] {
+		found := false
+		for _, param := range params {
+			if param.Key == standard.Key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			params = append(params, standard)
+		}
+	}
+	return params
+}
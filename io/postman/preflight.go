@@ -0,0 +1,15 @@
+package postman
+
+import (
+	"context"
+	"fmt"
+)
+
+// Preflight verifies the API key and that the configured collection is reachable.
+// It bypasses the circuit breaker so startup checks never trip it.
+func (c *Client) Preflight(ctx context.Context) error {
+	if _, err := c.executeGetCollection(ctx, c.config.CollectionID); err != nil {
+		return fmt.Errorf("postman collection %s: %w", c.config.CollectionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// previewContextLines is the number of unchanged lines shown around each change
+const previewContextLines = 3
+
+// previewEntry is one request item rendered for the preview
+type previewEntry struct {
+	label string   // folder path and item name
+	lines []string // indented JSON of the item
+	order int
+}
+
+// buildPreview renders the difference between two versions of a collection as a unified diff,
+// with one file per request item that was added, modified (including deprecations) or removed.
+// Items are matched by method and URL, so moved or renamed items show up as modifications.
+func buildPreview(before, after *models.PostmanCollection) string {
+	old := previewEntries(before)
+	updated := previewEntries(after)
+
+	keys := make([]string, 0, len(updated)+len(old))
+	for key := range updated {
+		keys = append(keys, key)
+	}
+	for key := range old {
+		if _, ok := updated[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	// Keep the order items have in the collection, removed items last
+	sort.SliceStable(keys, func(i, j int) bool {
+		return entryOrder(updated, old, keys[i]) < entryOrder(updated, old, keys[j])
+	})
+
+	var b strings.Builder
+	for _, key := range keys {
+		oldEntry, hadOld := old[key]
+		newEntry, hasNew := updated[key]
+
+		switch {
+		case !hadOld:
+			fmt.Fprintf(&b, "--- /dev/null\n+++ b/%s\n", newEntry.label)
+			writeHunks(&b, nil, newEntry.lines)
+		case !hasNew:
+			fmt.Fprintf(&b, "--- a/%s\n+++ /dev/null\n", oldEntry.label)
+			writeHunks(&b, oldEntry.lines, nil)
+		case strings.Join(oldEntry.lines, "\n") != strings.Join(newEntry.lines, "\n"):
+			fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", oldEntry.label, newEntry.label)
+			writeHunks(&b, oldEntry.lines, newEntry.lines)
+		}
+	}
+	return b.String()
+}
+
+func entryOrder(updated, old map[string]previewEntry, key string) int {
+	if entry, ok := updated[key]; ok {
+		return entry.order
+	}
+	return len(updated) + old[key].order
+}
+
+// previewEntries indexes the request items of a collection by method and raw URL
+func previewEntries(collection *models.PostmanCollection) map[string]previewEntry {
+	entries := make(map[string]previewEntry)
+	var walk func(items []models.PostmanItem, folders []string)
+	walk = func(items []models.PostmanItem, folders []string) {
+		for _, item := range items {
+			if item.Request == nil {
+				walk(item.Items, append(folders[:len(folders):len(folders)], item.Name))
+				continue
+			}
+
+			data, err := json.MarshalIndent(item, "", "  ")
+			if err != nil {
+				continue
+			}
+			key := item.Request.Method + " " + item.Request.URL.Raw
+			entries[key] = previewEntry{
+				label: strings.Join(append(folders[:len(folders):len(folders)], item.Name), "/"),
+				lines: strings.Split(string(data), "\n"),
+				order: len(entries),
+			}
+		}
+	}
+	walk(collection.Items, nil)
+	return entries
+}
+
+// cloneCollection deep-copies a collection so it can be compared after changes are applied
+func cloneCollection(collection *models.PostmanCollection) (*models.PostmanCollection, error) {
+	data, err := json.Marshal(collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy collection: %w", err)
+	}
+	var clone models.PostmanCollection
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to copy collection: %w", err)
+	}
+	return &clone, nil
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines computes a minimal edit script turning a into b from their longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// writeHunks writes the changes between a and b as unified diff hunks
+func writeHunks(b *strings.Builder, a, c []string) {
+	ops := diffLines(a, c)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			return
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		from := max(start-previewContextLines, 0)
+		end := start
+		for end < len(ops) {
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*previewContextLines {
+				break
+			}
+			end = next + 1
+		}
+		to := min(end+previewContextLines, len(ops))
+
+		// Line numbers of the hunk in both versions
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldLen, newLen := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldLen++
+			}
+			if op.kind != '-' {
+				newLen++
+			}
+		}
+		if oldLen == 0 {
+			oldStart--
+		}
+		if newLen == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+		for _, op := range ops[from:to] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			b.WriteByte('\n')
+		}
+		start = to
+	}
+}
@@ -0,0 +1,43 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const provenancePrefix = "<!-- generated-by: pr-documentator"
+
+var provenancePattern = regexp.MustCompile(`\n*<!-- generated-by: pr-documentator[^>]*-->\s*$`)
+
+// ProvenanceMarker builds the description footer identifying items generated from an analysis
+func ProvenanceMarker(analysis *models.AnalysisResponse) string {
+	if analysis == nil || analysis.PRNumber == 0 {
+		return provenancePrefix + " -->"
+	}
+	if analysis.Repository != "" {
+		return fmt.Sprintf("%s %s PR#%d -->", provenancePrefix, analysis.Repository, analysis.PRNumber)
+	}
+	return fmt.Sprintf("%s PR#%d -->", provenancePrefix, analysis.PRNumber)
+}
+
+// IsGenerated reports whether a description carries a pr-documentator provenance marker
+func IsGenerated(description string) bool {
+	return strings.Contains(description, provenancePrefix)
+}
+
+// StripProvenance removes the provenance marker from a description
+func StripProvenance(description string) string {
+	return provenancePattern.ReplaceAllString(description, "")
+}
+
+// withProvenance appends the provenance marker to a description, replacing any existing one
+func withProvenance(description string, analysis *models.AnalysisResponse) string {
+	description = StripProvenance(description)
+	if description == "" {
+		return ProvenanceMarker(analysis)
+	}
+	return description + "\n\n" + ProvenanceMarker(analysis)
+}
@@ -0,0 +1,143 @@
+package postman
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/pkg/backoff"
+)
+
+// rateLimitTracker remembers the rate-limit state reported by the last Postman response
+type rateLimitTracker struct {
+	mu        sync.Mutex
+	remaining int
+	known     bool
+	resetAt   time.Time
+}
+
+// observe records X-RateLimit-Remaining and X-RateLimit-Reset from a Postman response
+func (t *rateLimitTracker) observe(header http.Header, now time.Time) {
+	remaining, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Remaining")))
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.remaining = remaining
+	t.known = true
+	t.resetAt = time.Time{}
+	if reset, ok := parseResetHeader(header.Get("X-RateLimit-Reset"), now); ok {
+		t.resetAt = reset
+	}
+}
+
+// delay returns how long to wait before the next request when remaining is at or below lowWatermark
+func (t *rateLimitTracker) delay(lowWatermark int, maxWait time.Duration, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.known || t.remaining > lowWatermark {
+		return 0
+	}
+
+	var wait time.Duration
+	if !t.resetAt.IsZero() {
+		wait = t.resetAt.Sub(now)
+	} else {
+		// No reset hint, back off harder the closer we are to the limit
+		wait = backoff.DefaultPolicy.Duration(lowWatermark - t.remaining)
+	}
+
+	return clampWait(wait, maxWait)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// parseResetHeader accepts either seconds until reset or a unix timestamp
+func parseResetHeader(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+	// Values this large can only be epoch timestamps
+	if seconds > 1_000_000_000 {
+		return time.Unix(seconds, 0), true
+	}
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
+
+func clampWait(wait, maxWait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	if maxWait > 0 && wait > maxWait {
+		return maxWait
+	}
+	return wait
+}
+
+// doWithRateLimit sends the request built by newRequest, backing off proactively when the
+// remaining quota is low and retrying 429 responses after the delay Postman indicates.
+// The caller owns the returned response body.
+func (c *Client) doWithRateLimit(ctx context.Context, operation string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if wait := c.rateLimit.delay(c.config.RateLimitLowWatermark, c.config.RateLimitMaxWait, time.Now()); wait > 0 {
+			c.logger.Warn("Postman rate limit nearly exhausted, backing off", "operation", operation, "wait", wait.String())
+			if err := backoff.Sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			c.auditKeyUse(operation, 0, err)
+			return nil, err
+		}
+		c.auditKeyUse(operation, resp.StatusCode, nil)
+		c.rateLimit.observe(resp.Header, time.Now())
+
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		c.metrics.IncrementCounter("postman_rate_limited_total", map[string]string{"operation": operation})
+		if attempt >= c.config.RateLimitMaxRetries {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = backoff.DefaultPolicy.Duration(attempt)
+		}
+		wait = clampWait(wait, c.config.RateLimitMaxWait)
+
+		c.logger.Warn("Postman rate limit hit, retrying", "operation", operation, "attempt", attempt+1, "wait", wait.String())
+		if err := backoff.Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// buildResponseExamples renders one Postman example per documented status code, ordered by code.
+// Routes without Responses fall back to a single 200 example from Response.
+func buildResponseExamples(route models.APIRoute) ([]models.PostmanResponse, error) {
+	if len(route.Responses) == 0 {
+		if len(route.Response) == 0 {
+			return nil, nil
+		}
+		example, err := newResponseExample("Success Response", http.StatusOK, route.Response)
+		if err != nil {
+			return nil, err
+		}
+		return []models.PostmanResponse{example}, nil
+	}
+
+	codes := make([]int, 0, len(route.Responses))
+	byCode := make(map[int]models.RouteResponse, len(route.Responses))
+	for key, resp := range route.Responses {
+		code, err := strconv.Atoi(key)
+		if err != nil || code < 100 || code > 599 {
+			continue
+		}
+		codes = append(codes, code)
+		byCode[code] = resp
+	}
+	sort.Ints(codes)
+
+	responses := make([]models.PostmanResponse, 0, len(codes))
+	for _, code := range codes {
+		resp := byCode[code]
+		name := resp.Description
+		if name == "" {
+			name = http.StatusText(code)
+		}
+		example, err := newResponseExample(name, code, resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, example)
+	}
+	return responses, nil
+}
+
+func newResponseExample(name string, code int, body map[string]any) (models.PostmanResponse, error) {
+	example := models.PostmanResponse{
+		Name:   name,
+		Status: http.StatusText(code),
+		Code:   code,
+		Header: []models.PostmanHeader{
+			{
+				Key:   "Content-Type",
+				Value: "application/json",
+			},
+		},
+	}
+	if len(body) > 0 {
+		bodyJSON, err := json.MarshalIndent(body, "", "  ")
+		if err != nil {
+			return example, fmt.Errorf("invalid %d response body: %w", code, err)
+		}
+		example.Body = string(bodyJSON)
+	}
+	return example, nil
+}
+
+// limitResponseExamples keeps at most MaxResponseExamples examples, chosen in this order: the
+// first success response, the first client error, the first server error, then the rest. Kept
+// examples stay in their original order.
+func (c *Client) limitResponseExamples(route models.APIRoute, examples []models.PostmanResponse) []models.PostmanResponse {
+	max := c.config.MaxResponseExamples
+	if max <= 0 || len(examples) <= max {
+		return examples
+	}
+
+	priority := make([]int, 0, len(examples))
+	chosen := make([]bool, len(examples))
+	for _, class := range []int{2, 4, 5} {
+		for i, example := range examples {
+			if example.Code/100 == class {
+				priority = append(priority, i)
+				chosen[i] = true
+				break
+			}
+		}
+	}
+	for i := range examples {
+		if !chosen[i] {
+			priority = append(priority, i)
+		}
+	}
+
+	keep := make([]bool, len(examples))
+	for _, i := range priority[:max] {
+		keep[i] = true
+	}
+	limited := make([]models.PostmanResponse, 0, max)
+	for i, example := range examples {
+		if keep[i] {
+			limited = append(limited, example)
+		}
+	}
+
+	c.logger.Debug("Dropped response examples over the limit",
+		"method", route.Method,
+		"path", route.Path,
+		"examples", len(examples),
+		"kept", len(limited),
+	)
+	return limited
+}
@@ -0,0 +1,68 @@
+package postman
+
+import (
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+const (
+	deprecatedMarker      = "[DEPRECATED]"
+	deprecatedPlaceholder = "This endpoint is deprecated." // description of items deprecated without one
+)
+
+// reviveItem replaces a deprecated item documenting route with item, for routes re-added after
+// being removed. Items marked inline are replaced in place; items in the deprecated folder are
+// moved back like new ones. When Claude gave no description, the one from before the deprecation
+// is restored.
+func (c *Client) reviveItem(collection *models.PostmanCollection, route models.APIRoute, item models.PostmanItem) bool {
+	loc, inFolder, ok := c.findDeprecatedItem(collection, route)
+	if !ok {
+		return false
+	}
+
+	if route.Description == "" {
+		item.Description = undeprecatedDescription(loc.item().Description)
+	}
+	if inFolder {
+		loc.remove()
+		c.addItem(collection, route, item)
+	} else {
+		*loc.item() = item
+	}
+
+	c.logger.Info("Revived deprecated Postman item", "method", route.Method, "path", route.Path, "from_folder", inFolder)
+	return true
+}
+
+// findDeprecatedItem locates the deprecated item documenting route, either marked inline or
+// moved to the deprecated folder
+func (c *Client) findDeprecatedItem(collection *models.PostmanCollection, route models.APIRoute) (loc itemLocation, inFolder, ok bool) {
+	if loc, ok := c.findItem(collection, route); ok {
+		item := loc.item()
+		deprecated := strings.HasPrefix(item.Name, deprecatedMarker) || strings.HasPrefix(item.Description, deprecatedMarker)
+		return loc, false, deprecated
+	}
+
+	folderName := c.config.DeprecatedFolder
+	if folderName == "" {
+		folderName = "_deprecated"
+	}
+	for i := range collection.Items {
+		folder := &collection.Items[i]
+		if folder.Request == nil && folder.Name == folderName {
+			loc, ok := c.findItemIn(&folder.Items, c.itemName(route), route)
+			return loc, true, ok
+		}
+	}
+	return itemLocation{}, false, false
+}
+
+// undeprecatedDescription strips the deprecation marker added by markItemAsDeprecated
+func undeprecatedDescription(description string) string {
+	description = strings.TrimSpace(strings.TrimPrefix(description, deprecatedMarker))
+	if description == deprecatedPlaceholder {
+		return ""
+	}
+	return description
+}
@@ -0,0 +1,125 @@
+package postman
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Warn(string, ...any)         {}
+func (nopLogger) Error(string, error, ...any) {}
+func (nopLogger) Fatal(string, error, ...any) {}
+
+type nopMetrics struct{}
+
+func (nopMetrics) IncrementCounter(string, map[string]string)        {}
+func (nopMetrics) AddCounter(string, float64, map[string]string)     {}
+func (nopMetrics) RecordDuration(string, float64, map[string]string) {}
+func (nopMetrics) SetGauge(string, float64, map[string]string)       {}
+
+// itemsFor returns every request item documenting route, wherever it is in the collection
+func itemsFor(items []models.PostmanItem, route models.APIRoute) []models.PostmanItem {
+	var found []models.PostmanItem
+	for _, item := range items {
+		if item.Request == nil {
+			found = append(found, itemsFor(item.Items, route)...)
+			continue
+		}
+		if strings.EqualFold(item.Request.Method, route.Method) && item.Request.URL.Raw == "{{baseUrl}}"+route.Path {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+func deprecatedFolderItems(collection *models.PostmanCollection, name string) []models.PostmanItem {
+	for _, item := range collection.Items {
+		if item.Request == nil && item.Name == name {
+			return item.Items
+		}
+	}
+	return nil
+}
+
+func TestDeprecateThenReaddRevivesItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		description string // when the route was first documented
+		readdedAs   string // "new" or "modified"
+		readdedDesc string // Claude's description when re-added
+		wantDesc    string // expected description, before the provenance marker
+	}{
+		{name: "inline, new route", mode: config.DeprecationModeInline, readdedAs: "new"},
+		{name: "inline, modified route", mode: config.DeprecationModeInline, readdedAs: "modified"},
+		{name: "inline, restores description", mode: config.DeprecationModeInline, description: "Lists users", readdedAs: "new", wantDesc: "Lists users"},
+		{name: "inline, new description", mode: config.DeprecationModeInline, description: "Lists users", readdedAs: "new", readdedDesc: "Lists active users", wantDesc: "Lists active users"},
+		{name: "folder, new route", mode: config.DeprecationModeFolder, readdedAs: "new"},
+		{name: "folder, modified route", mode: config.DeprecationModeFolder, readdedAs: "modified"},
+		{name: "folder, restores description", mode: config.DeprecationModeFolder, description: "Lists users", readdedAs: "new", wantDesc: "Lists users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(config.PostmanConfig{
+				DeprecationMode:  tt.mode,
+				DeprecatedFolder: "_deprecated",
+			}, nopLogger{}, nopMetrics{})
+
+			route := models.APIRoute{Method: "GET", Path: "/api/users", Description: tt.description}
+			collection := &models.PostmanCollection{}
+			original, err := c.buildItem(route, &models.AnalysisResponse{})
+			if err != nil {
+				t.Fatalf("buildItem: %v", err)
+			}
+			c.addItem(collection, route, original)
+
+			if !c.markItemAsDeprecated(collection, route) {
+				t.Fatal("markItemAsDeprecated did not find the item")
+			}
+			if tt.mode == config.DeprecationModeFolder && len(deprecatedFolderItems(collection, "_deprecated")) != 1 {
+				t.Fatal("deprecated item was not moved to the _deprecated folder")
+			}
+
+			readded := models.APIRoute{Method: route.Method, Path: route.Path, Description: tt.readdedDesc}
+			analysis := &models.AnalysisResponse{}
+			if tt.readdedAs == "new" {
+				analysis.NewRoutes = []models.APIRoute{readded}
+			} else {
+				analysis.ModifiedRoutes = []models.APIRoute{readded}
+			}
+			update := &models.PostmanUpdate{}
+			c.applyFullUpdate(collection, analysis, update)
+
+			if update.ItemsModified != 1 || update.ItemsAdded != 0 {
+				t.Errorf("got %d modified and %d added items, want the deprecated item revived", update.ItemsModified, update.ItemsAdded)
+			}
+
+			items := itemsFor(collection.Items, route)
+			if len(items) != 1 {
+				t.Fatalf("got %d items for %s %s, want exactly 1", len(items), route.Method, route.Path)
+			}
+			item := items[0]
+			if strings.HasPrefix(item.Name, deprecatedMarker) {
+				t.Errorf("name %q still carries %s", item.Name, deprecatedMarker)
+			}
+			if strings.Contains(item.Description, deprecatedMarker) || strings.Contains(item.Description, deprecatedPlaceholder) {
+				t.Errorf("description %q still marks the item as deprecated", item.Description)
+			}
+			if tt.wantDesc != "" && !strings.HasPrefix(item.Description, tt.wantDesc) {
+				t.Errorf("description = %q, want %q", item.Description, tt.wantDesc)
+			}
+			if tt.mode == config.DeprecationModeFolder {
+				if n := len(deprecatedFolderItems(collection, "_deprecated")); n != 0 {
+					t.Errorf("_deprecated folder still holds %d items", n)
+				}
+			}
+		})
+	}
+}
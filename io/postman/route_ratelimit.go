@@ -0,0 +1,78 @@
+package postman
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// defaultRateLimitHeaders are documented for rate-limited routes when the analysis named none
+var defaultRateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// withRateLimitHeaders adds the route's rate-limit headers to every response example and a
+// 429 example when the analysis didn't document one
+func withRateLimitHeaders(examples []models.PostmanResponse, limit *models.RouteRateLimit) []models.PostmanResponse {
+	if limit == nil {
+		return examples
+	}
+
+	names := limit.Headers
+	if len(names) == 0 {
+		names = defaultRateLimitHeaders
+	}
+
+	limited := false
+	for i := range examples {
+		if examples[i].Code == http.StatusTooManyRequests {
+			limited = true
+		}
+		examples[i].Header = append(examples[i].Header, rateLimitHeaders(names, limit, examples[i].Code)...)
+	}
+	if !limited {
+		examples = append(examples, models.PostmanResponse{
+			Name:   "Rate limit exceeded",
+			Status: http.StatusText(http.StatusTooManyRequests),
+			Code:   http.StatusTooManyRequests,
+			Header: append([]models.PostmanHeader{{Key: "Content-Type", Value: "application/json"}},
+				rateLimitHeaders(append(names[:len(names):len(names)], "Retry-After"), limit, http.StatusTooManyRequests)...),
+		})
+	}
+	return examples
+}
+
+// rateLimitHeaders renders example values consistent with the limit for a response with code.
+// Counts are left empty when the limit is unknown.
+func rateLimitHeaders(names []string, limit *models.RouteRateLimit, code int) []models.PostmanHeader {
+	window := 60
+	if d, err := time.ParseDuration(limit.Window); err == nil && d >= time.Second {
+		window = int(d.Seconds())
+	}
+
+	headers := make([]models.PostmanHeader, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		// Retry-After only accompanies rejected requests
+		if seen[key] || (key == "retry-after" && code != http.StatusTooManyRequests) {
+			continue
+		}
+		seen[key] = true
+
+		var value string
+		switch {
+		case strings.HasSuffix(key, "-limit") && limit.Limit > 0:
+			value = strconv.Itoa(limit.Limit)
+		case strings.HasSuffix(key, "-remaining") && code == http.StatusTooManyRequests:
+			value = "0"
+		case strings.HasSuffix(key, "-remaining") && limit.Limit > 0:
+			value = strconv.Itoa(limit.Limit - 1)
+		case strings.HasSuffix(key, "-reset"), key == "retry-after":
+			value = strconv.Itoa(window)
+		}
+		headers = append(headers, models.PostmanHeader{Key: name, Value: value})
+	}
+	return headers
+}
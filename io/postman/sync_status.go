@@ -0,0 +1,85 @@
+package postman
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// syncTracker records the outcome of collection updates per collection
+type syncTracker struct {
+	mu       sync.Mutex
+	statuses map[string]*models.SyncStatus
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{statuses: make(map[string]*models.SyncStatus)}
+}
+
+// record updates the collection's status after an UpdateCollection attempt. collection is the
+// updated collection and is only used on success.
+func (t *syncTracker) record(collectionID string, analysis *models.AnalysisResponse, collection *models.PostmanCollection, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, ok := t.statuses[collectionID]
+	if !ok {
+		status = &models.SyncStatus{CollectionID: collectionID}
+		t.statuses[collectionID] = status
+	}
+
+	now := time.Now()
+	status.LastAttemptAt = now
+
+	if err != nil {
+		status.ConsecutiveFailures++
+		status.LastError = err.Error()
+		status.Health = models.SyncHealthDegraded
+		if status.ConsecutiveFailures >= models.SyncFailingThreshold {
+			status.Health = models.SyncHealthFailing
+		}
+		return
+	}
+
+	status.Health = models.SyncHealthHealthy
+	status.ConsecutiveFailures = 0
+	status.LastError = ""
+	status.LastSyncAt = &now
+	status.LastRepository = analysis.Repository
+	status.LastPRNumber = analysis.PRNumber
+	if collection != nil {
+		status.TotalItems = countRequests(collection.Items)
+	}
+}
+
+// snapshot returns a copy of every tracked status, ordered by collection ID
+func (t *syncTracker) snapshot() []models.SyncStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]models.SyncStatus, 0, len(t.statuses))
+	for _, status := range t.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].CollectionID < statuses[j].CollectionID })
+	return statuses
+}
+
+// countRequests counts request items, descending into folders
+func countRequests(items []models.PostmanItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Request != nil {
+			count++
+		}
+		count += countRequests(item.Items)
+	}
+	return count
+}
+
+// SyncStatuses reports the sync status of every collection updated since startup
+func (c *Client) SyncStatuses() []models.SyncStatus {
+	return c.sync.snapshot()
+}
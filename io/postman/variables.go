@@ -0,0 +1,81 @@
+package postman
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// variableRef matches {{name}} references in URLs, headers, bodies and auth values
+var variableRef = regexp.MustCompile(`\{\{([^{}\s]+)\}\}`)
+
+// ensureCollectionVariables adds an empty collection variable for every {{name}} referenced by a
+// request but not defined in the collection, and returns the added names in order. Dynamic
+// variables such as {{$guid}} are built into Postman and skipped. Environment values still take
+// precedence over the empty defaults.
+func ensureCollectionVariables(collection *models.PostmanCollection) []string {
+	defined := make(map[string]bool, len(collection.Variables))
+	for _, v := range collection.Variables {
+		defined[v.Key] = true
+	}
+
+	missing := make(map[string]bool)
+	collect := func(values ...string) {
+		for _, value := range values {
+			for _, match := range variableRef.FindAllStringSubmatch(value, -1) {
+				name := match[1]
+				if !defined[name] && !strings.HasPrefix(name, "$") {
+					missing[name] = true
+				}
+			}
+		}
+	}
+
+	collectAuth(collection.Auth, collect)
+	collectRequests(collection.Items, collect)
+
+	added := make([]string, 0, len(missing))
+	for name := range missing {
+		added = append(added, name)
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		collection.Variables = append(collection.Variables, models.PostmanVariable{Key: name, Value: "", Type: "string"})
+	}
+	return added
+}
+
+// collectRequests passes every variable-bearing request value to collect, descending into folders
+func collectRequests(items []models.PostmanItem, collect func(values ...string)) {
+	for _, item := range items {
+		if req := item.Request; req != nil {
+			collect(req.URL.Raw)
+			collect(req.URL.Host...)
+			collect(req.URL.Path...)
+			for _, q := range req.URL.Query {
+				collect(q.Value)
+			}
+			for _, h := range req.Header {
+				collect(h.Value)
+			}
+			if req.Body != nil {
+				collect(req.Body.Raw)
+			}
+			collectAuth(req.Auth, collect)
+		}
+		collectRequests(item.Items, collect)
+	}
+}
+
+func collectAuth(auth *models.PostmanAuth, collect func(values ...string)) {
+	if auth == nil {
+		return
+	}
+	for _, attrs := range [][]models.PostmanAuthAttribute{auth.Bearer, auth.APIKey, auth.Basic} {
+		for _, attr := range attrs {
+			collect(attr.Value)
+		}
+	}
+}
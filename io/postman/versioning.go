@@ -0,0 +1,41 @@
+package postman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// errWriteConflict means the collection changed between our GET and PUT
+var errWriteConflict = errors.New("collection was modified since it was read")
+
+// collectionVersion identifies the state a collection was read at. Postman may send an ETag, which
+// is used for conditional writes; info.updatedAt is the fallback when it doesn't.
+type collectionVersion struct {
+	etag      string
+	updatedAt string
+}
+
+type versionedCollection struct {
+	collection *models.PostmanCollection
+	version    collectionVersion
+}
+
+// checkUnchanged re-reads the collection version right before an unconditional write. It can only
+// narrow the race window, not close it; collections without an ETag or updatedAt are never flagged.
+func (c *Client) checkUnchanged(ctx context.Context, collectionID string, read collectionVersion) error {
+	if read.etag != "" || read.updatedAt == "" {
+		return nil
+	}
+
+	current, err := c.getVersionedCollection(ctx, collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to check collection version: %w", err)
+	}
+	if current.version.updatedAt != read.updatedAt {
+		return errWriteConflict
+	}
+	return nil
+}
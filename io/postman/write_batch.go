@@ -0,0 +1,280 @@
+package postman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// pendingWrite is one analysis waiting to be applied to a collection
+type pendingWrite struct {
+	analysis *models.AnalysisResponse
+	done     chan writeResult
+}
+
+type writeResult struct {
+	update *models.PostmanUpdate
+	err    error
+}
+
+// collectionWrites holds the queue of one collection. lock serializes read-modify-write cycles,
+// so concurrent PRs can't overwrite each other's changes; it's a channel so acquiring it can time out.
+type collectionWrites struct {
+	lock      chan struct{}
+	pendingMu sync.Mutex
+	pending   []*pendingWrite
+	scheduled bool
+}
+
+// Write lock acquisition outcomes reported to writeCoalescer.observe
+const (
+	writeLockFree      = "free"      // no write was in progress
+	writeLockContended = "contended" // acquired after waiting for another write
+	writeLockTimeout   = "timeout"   // gave up after lockTimeout, the batch was answered busy
+)
+
+// writeCoalescer batches updates per collection: analyses arriving within window of the first
+// are applied to a single fetched copy and saved with one PUT. A batch that can't take the
+// collection's write lock within lockTimeout (0 waits indefinitely) fails with POSTMAN_BUSY.
+type writeCoalescer struct {
+	window      time.Duration
+	lockTimeout time.Duration
+	write       func(collectionID string, batch []*pendingWrite)
+	observe     func(collectionID, outcome string, waited time.Duration, batchSize int)
+
+	mu          sync.Mutex
+	collections map[string]*collectionWrites
+}
+
+func newWriteCoalescer(window, lockTimeout time.Duration, write func(collectionID string, batch []*pendingWrite), observe func(collectionID, outcome string, waited time.Duration, batchSize int)) *writeCoalescer {
+	return &writeCoalescer{
+		window:      window,
+		lockTimeout: lockTimeout,
+		write:       write,
+		observe:     observe,
+		collections: make(map[string]*collectionWrites),
+	}
+}
+
+// submit queues analysis for collectionID and waits for the batch containing it to be written.
+// A cancelled ctx stops the wait, not the write.
+func (w *writeCoalescer) submit(ctx context.Context, collectionID string, analysis *models.AnalysisResponse) (*models.PostmanUpdate, error) {
+	w.mu.Lock()
+	cw, ok := w.collections[collectionID]
+	if !ok {
+		cw = &collectionWrites{lock: make(chan struct{}, 1)}
+		w.collections[collectionID] = cw
+	}
+	w.mu.Unlock()
+
+	write := &pendingWrite{analysis: analysis, done: make(chan writeResult, 1)}
+	cw.pendingMu.Lock()
+	cw.pending = append(cw.pending, write)
+	if !cw.scheduled {
+		cw.scheduled = true
+		go w.flush(collectionID, cw)
+	}
+	cw.pendingMu.Unlock()
+
+	select {
+	case result := <-write.done:
+		return result.update, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush writes everything queued for a collection once the window has passed. Analyses queued
+// while a write is in progress schedule the next flush, which waits for it to finish.
+func (w *writeCoalescer) flush(collectionID string, cw *collectionWrites) {
+	if w.window > 0 {
+		time.Sleep(w.window)
+	}
+
+	if !w.acquire(collectionID, cw) {
+		batch := cw.take()
+		err := pkgerrors.NewUnavailableError("postman").
+			WithCode(pkgerrors.CodePostmanBusy).
+			WithContext("reason", fmt.Sprintf("another write to the collection has held its lock for over %s", w.lockTimeout))
+		for _, write := range batch {
+			write.done <- writeResult{err: err}
+		}
+		return
+	}
+	defer func() { <-cw.lock }()
+
+	if batch := cw.take(); len(batch) > 0 {
+		w.write(collectionID, batch)
+	}
+}
+
+// acquire takes the collection's write lock, giving up after lockTimeout
+func (w *writeCoalescer) acquire(collectionID string, cw *collectionWrites) bool {
+	select {
+	case cw.lock <- struct{}{}:
+		w.observe(collectionID, writeLockFree, 0, 0)
+		return true
+	default:
+	}
+
+	start := time.Now()
+	var timeout <-chan time.Time
+	if w.lockTimeout > 0 {
+		timer := time.NewTimer(w.lockTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case cw.lock <- struct{}{}:
+		w.observe(collectionID, writeLockContended, time.Since(start), 0)
+		return true
+	case <-timeout:
+		cw.pendingMu.Lock()
+		batchSize := len(cw.pending)
+		cw.pendingMu.Unlock()
+		w.observe(collectionID, writeLockTimeout, time.Since(start), batchSize)
+		return false
+	}
+}
+
+// take removes the queued writes, letting the next submit schedule a new flush
+func (cw *collectionWrites) take() []*pendingWrite {
+	cw.pendingMu.Lock()
+	defer cw.pendingMu.Unlock()
+	batch := cw.pending
+	cw.pending = nil
+	cw.scheduled = false
+	return batch
+}
+
+// observeWriteLock reports contention on a collection's write lock
+func (c *Client) observeWriteLock(collectionID, outcome string, waited time.Duration, batchSize int) {
+	c.metrics.IncrementCounter("postman_write_lock_total", map[string]string{"outcome": outcome})
+	if outcome == writeLockFree {
+		return
+	}
+	c.metrics.RecordDuration("postman_write_lock_wait_seconds", waited.Seconds(), nil)
+	if outcome == writeLockTimeout {
+		c.logger.Warn("Postman collection write lock busy, answering queued updates with busy status",
+			"collection_id", collectionID,
+			"waited", waited,
+			"batch_size", batchSize,
+		)
+	}
+}
+
+// batchAttempt is the result of applying a batch to one read of the collection
+type batchAttempt struct {
+	collection *models.PostmanCollection
+	applied    []*pendingWrite
+	updates    map[*pendingWrite]*models.PostmanUpdate
+	rejected   map[*pendingWrite]error // analyses that couldn't be applied
+}
+
+// writeBatch fetches the collection once, applies every analysis of the batch in order and saves
+// the result with a single PUT. When the collection changed in between, the whole batch is
+// re-applied to a fresh copy, up to WriteConflictRetries times.
+func (c *Client) writeBatch(collectionID string, batch []*pendingWrite) {
+	ctx := context.Background()
+	c.metrics.RecordDuration("postman_write_batch_size", float64(len(batch)), nil)
+
+	var (
+		attempt *batchAttempt
+		err     error
+	)
+	for retries := 0; ; retries++ {
+		attempt, err = c.attemptBatch(ctx, collectionID, batch)
+		if !errors.Is(err, errWriteConflict) {
+			break
+		}
+		if retries >= c.config.WriteConflictRetries {
+			c.metrics.IncrementCounter("postman_write_conflicts_total", map[string]string{"outcome": "exhausted"})
+			err = pkgerrors.NewExternalError("postman",
+				fmt.Sprintf("collection kept changing, gave up after %d retries", retries)).WithCause(err)
+			break
+		}
+		c.metrics.IncrementCounter("postman_write_conflicts_total", map[string]string{"outcome": "retried"})
+		c.logger.Warn("Postman collection changed since it was read, re-applying batch",
+			"collection_id", collectionID,
+			"batch_size", len(batch),
+			"retry", retries+1,
+		)
+	}
+
+	if attempt == nil {
+		for _, write := range batch {
+			c.sync.record(collectionID, write.analysis, nil, err)
+			write.done <- writeResult{err: err}
+		}
+		return
+	}
+
+	for write, applyErr := range attempt.rejected {
+		c.sync.record(collectionID, write.analysis, nil, applyErr)
+		write.done <- writeResult{err: applyErr}
+	}
+
+	for _, write := range attempt.applied {
+		updated := attempt.updates[write]
+		c.auditWrite(collectionID, write.analysis, updated, err)
+		c.sync.record(collectionID, write.analysis, attempt.collection, err)
+		if err != nil {
+			write.done <- writeResult{err: err}
+			continue
+		}
+
+		c.logger.Info("Successfully updated Postman collection",
+			"collection_id", collectionID,
+			"repository", write.analysis.Repository,
+			"pr_number", write.analysis.PRNumber,
+			"batch_size", len(attempt.applied),
+			"items_added", updated.ItemsAdded,
+			"items_modified", updated.ItemsModified,
+			"items_deleted", updated.ItemsDeleted,
+			"items_failed", updated.ItemsFailed,
+		)
+		write.done <- writeResult{update: updated}
+	}
+}
+
+// attemptBatch reads the collection, applies the batch and writes it back. A nil attempt means
+// the collection couldn't be read.
+func (c *Client) attemptBatch(ctx context.Context, collectionID string, batch []*pendingWrite) (*batchAttempt, error) {
+	versioned, err := c.getVersionedCollection(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	attempt := &batchAttempt{
+		collection: versioned.collection,
+		updates:    make(map[*pendingWrite]*models.PostmanUpdate, len(batch)),
+		rejected:   make(map[*pendingWrite]error),
+	}
+	for _, write := range batch {
+		updated, err := c.applyAnalysis(versioned.collection, collectionID, write.analysis)
+		if err != nil {
+			attempt.rejected[write] = err
+			continue
+		}
+		attempt.updates[write] = updated
+		attempt.applied = append(attempt.applied, write)
+	}
+	if len(attempt.applied) == 0 {
+		return attempt, nil
+	}
+
+	if err := c.checkUnchanged(ctx, collectionID, versioned.version); err != nil {
+		return attempt, err
+	}
+
+	// Send the updated collection back to Postman
+	if err := c.putCollection(ctx, collectionID, versioned.collection, versioned.version); err != nil {
+		return attempt, fmt.Errorf("failed to save updated collection: %w", err)
+	}
+	return attempt, nil
+}
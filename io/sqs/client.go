@@ -0,0 +1,236 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	// waitTimeSeconds long-polls ReceiveMessage, the maximum SQS allows
+	waitTimeSeconds = 20
+	// requestTimeout bounds every SQS call beyond the long poll
+	requestTimeout = 30 * time.Second
+	service        = "sqs"
+)
+
+// Client is a minimal Amazon SQS client speaking the JSON protocol with Signature Version 4.
+// It receives from one queue, deleting messages once handled, and sends results to another,
+// which is all the queue ingest mode needs.
+type Client struct {
+	config     config.QueueConfig
+	logger     interfaces.Logger
+	httpClient *http.Client
+	endpoint   string
+	region     string
+	creds      credentials
+}
+
+// message is an SQS message as returned by ReceiveMessage
+type message struct {
+	MessageID     string `json:"MessageId"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+	Body          string `json:"Body"`
+}
+
+// NewClient creates a client for the queue URL in cfg. The region comes from cfg.Region or,
+// when empty, from the sqs.<region>.amazonaws.com host of the queue URL.
+func NewClient(cfg config.QueueConfig, logger interfaces.Logger) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, pkgerrors.NewValidationError(fmt.Sprintf("invalid SQS queue URL %q", cfg.URL))
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = regionFromHost(u.Hostname())
+	}
+	if region == "" {
+		return nil, pkgerrors.NewValidationError("AWS_REGION must be set for SQS queue URL " + cfg.URL)
+	}
+
+	return &Client{
+		config:     cfg,
+		logger:     logger,
+		httpClient: &http.Client{Timeout: requestTimeout + waitTimeSeconds*time.Second},
+		endpoint:   u.Scheme + "://" + u.Host + "/",
+		region:     region,
+		creds: credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+		},
+	}, nil
+}
+
+// regionFromHost extracts the region from hosts like sqs.us-east-1.amazonaws.com
+func regionFromHost(host string) string {
+	parts := strings.Split(host, ".")
+	if len(parts) >= 4 && parts[0] == service && parts[len(parts)-2] == "amazonaws" {
+		return parts[1]
+	}
+	return ""
+}
+
+// Consume long-polls the queue and calls handler for every message until ctx is done. Handled
+// messages are deleted; failed ones become visible again after the visibility timeout, so SQS
+// redelivers them or moves them to the queue's dead-letter queue. The visibility timeout is
+// extended while a message is being handled.
+func (c *Client) Consume(ctx context.Context, handler func(ctx context.Context, data []byte) error) error {
+	c.logger.Info("Polling SQS queue", "queue_url", c.config.URL)
+
+	for {
+		messages, err := c.receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, msg := range messages {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.handle(ctx, msg, handler)
+		}
+	}
+}
+
+func (c *Client) handle(ctx context.Context, msg message, handler func(ctx context.Context, data []byte) error) {
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	go c.heartbeat(heartbeatCtx, msg)
+
+	if err := handleSafely(ctx, handler, []byte(msg.Body)); err != nil {
+		c.logger.Error("Failed to handle SQS message, leaving it for redelivery", err, "message_id", msg.MessageID)
+		return
+	}
+	stopHeartbeat()
+
+	if err := c.call(ctx, "DeleteMessage", map[string]any{
+		"QueueUrl":      c.config.URL,
+		"ReceiptHandle": msg.ReceiptHandle,
+	}, nil); err != nil {
+		c.logger.Error("Failed to delete handled SQS message", err, "message_id", msg.MessageID)
+	}
+}
+
+// handleSafely runs handler, turning a panic into an error so one bad message can't stop the consumer
+func handleSafely(ctx context.Context, handler func(ctx context.Context, data []byte) error, data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, data)
+}
+
+// heartbeat keeps msg invisible to other consumers while it is handled, renewing the visibility
+// timeout halfway through each period
+func (c *Client) heartbeat(ctx context.Context, msg message) {
+	ticker := time.NewTicker(c.config.VisibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.call(ctx, "ChangeMessageVisibility", map[string]any{
+				"QueueUrl":          c.config.URL,
+				"ReceiptHandle":     msg.ReceiptHandle,
+				"VisibilityTimeout": int(c.config.VisibilityTimeout.Seconds()),
+			}, nil); err != nil && ctx.Err() == nil {
+				c.logger.Warn("Failed to extend SQS message visibility", "message_id", msg.MessageID, "error", err)
+			}
+		}
+	}
+}
+
+func (c *Client) receive(ctx context.Context) ([]message, error) {
+	var resp struct {
+		Messages []message `json:"Messages"`
+	}
+	err := c.call(ctx, "ReceiveMessage", map[string]any{
+		"QueueUrl":            c.config.URL,
+		"MaxNumberOfMessages": 1,
+		"WaitTimeSeconds":     waitTimeSeconds,
+		"VisibilityTimeout":   int(c.config.VisibilityTimeout.Seconds()),
+	}, &resp)
+	return resp.Messages, err
+}
+
+// Publish sends data to the output queue URL configured as QUEUE_OUTPUT_SUBJECT
+func (c *Client) Publish(ctx context.Context, data []byte) error {
+	if c.config.OutputSubject == "" {
+		return nil
+	}
+	return c.call(ctx, "SendMessage", map[string]any{
+		"QueueUrl":    c.config.OutputSubject,
+		"MessageBody": string(data),
+	}, nil)
+}
+
+// Close releases idle connections
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// call invokes an SQS JSON protocol action, decoding the response into out when not nil
+func (c *Client) call(ctx context.Context, action string, input map[string]any, out any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return pkgerrors.WrapError(err, "failed to marshal SQS request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return pkgerrors.NewExternalError(service, "failed to create request").WithCause(err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS."+action)
+	signRequest(req, body, c.creds, c.region, service, time.Now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pkgerrors.NewUnavailableError(service).WithCause(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pkgerrors.NewExternalError(service, "failed to read response").WithCause(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		message := fmt.Sprintf("%s failed with status %d: %s %s", action, resp.StatusCode, apiErr.Type, apiErr.Message)
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return pkgerrors.NewUnauthorizedError(message)
+		}
+		return pkgerrors.NewExternalError(service, message)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return pkgerrors.NewExternalError(service, "failed to decode response").WithCause(err)
+		}
+	}
+	return nil
+}
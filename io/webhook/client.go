@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/backoff"
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+const (
+	SignatureHeader = "X-PR-Documentator-Signature-256"
+	EventHeader     = "X-PR-Documentator-Event"
+	AnalysisEvent   = "analysis.completed"
+)
+
+// Client delivers analysis results to an external webhook without blocking the caller
+type Client struct {
+	httpClient *http.Client
+	config     config.OutputWebhookConfig
+	logger     interfaces.Logger
+	metrics    interfaces.MetricsCollector
+	inFlight   sync.WaitGroup
+}
+
+// NewClient creates a new output webhook client
+func NewClient(cfg config.OutputWebhookConfig, logger interfaces.Logger, metrics interfaces.MetricsCollector) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		config:     cfg,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Notify posts the analysis result in the background, retrying failed deliveries with backoff
+func (c *Client) Notify(ctx context.Context, resp *models.AnalysisResponse) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		c.logger.Error("Failed to marshal analysis for output webhook", err)
+		return
+	}
+
+	c.inFlight.Add(1)
+	go func() {
+		defer c.inFlight.Done()
+
+		// Detach from the request context, which is cancelled once the response is written
+		deliveryCtx, cancel := context.WithTimeout(context.Background(), c.config.Timeout*time.Duration(c.config.MaxRetries+1)+time.Minute)
+		defer cancel()
+
+		err := backoff.Retry(deliveryCtx, backoff.DefaultPolicy, c.config.MaxRetries+1, isRetryable, func(attempt int) error {
+			return c.deliver(deliveryCtx, body)
+		})
+
+		status := "success"
+		if err != nil {
+			status = "error"
+			c.logger.Error("Failed to deliver output webhook", err, "url", c.config.URL, "pr_number", resp.PRNumber)
+		} else {
+			c.logger.Debug("Delivered output webhook", "url", c.config.URL, "pr_number", resp.PRNumber)
+		}
+		c.metrics.IncrementCounter("output_webhook_deliveries_total", map[string]string{"status": status})
+	}()
+}
+
+// Wait blocks until in-flight deliveries finish or ctx is done
+func (c *Client) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) deliver(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return pkgerrors.NewExternalError("output_webhook", "failed to create request").WithCause(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, AnalysisEvent)
+	if c.config.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(body, c.config.Secret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pkgerrors.NewUnavailableError("output_webhook").WithCause(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return pkgerrors.NewRateLimitError("output_webhook")
+	case resp.StatusCode >= 500:
+		return pkgerrors.NewUnavailableError("output_webhook").WithContext("status_code", resp.StatusCode)
+	default:
+		return pkgerrors.NewExternalError("output_webhook", fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+}
+
+// Sign computes the sha256 HMAC signature header value for body, matching GitHub's webhook format
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isRetryable retries transient failures but not client errors
+func isRetryable(err error) bool {
+	appErr, ok := pkgerrors.AsAppError(err)
+	if !ok {
+		return true
+	}
+	return appErr.Type == pkgerrors.ErrorTypeUnavailable || appErr.Type == pkgerrors.ErrorTypeRateLimit
+}
@@ -0,0 +1,174 @@
+package asyncapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// Version is the AsyncAPI specification version documents are generated for
+const Version = "2.6.0"
+
+// Document is an AsyncAPI 2.x document, limited to the parts generated from an analysis
+type Document struct {
+	AsyncAPI string             `json:"asyncapi"`
+	Info     Info               `json:"info"`
+	Channels map[string]Channel `json:"channels"`
+}
+
+// Info describes the documented application
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Channel is an AsyncAPI channel item. In AsyncAPI 2.x, subscribe documents messages the
+// application sends and publish messages it receives.
+type Channel struct {
+	Description string     `json:"description,omitempty"`
+	Subscribe   *Operation `json:"subscribe,omitempty"`
+	Publish     *Operation `json:"publish,omitempty"`
+	Bindings    any        `json:"bindings,omitempty"`
+	Removed     bool       `json:"x-removed,omitempty"` // the PR removed the channel
+	Change      string     `json:"x-change,omitempty"`  // new, modified or deleted
+}
+
+// Operation is a send or receive operation on a channel
+type Operation struct {
+	OperationID string  `json:"operationId"`
+	Summary     string  `json:"summary,omitempty"`
+	Message     Message `json:"message"`
+}
+
+// Message is the message carried by an operation
+type Message struct {
+	Name        string           `json:"name,omitempty"`
+	ContentType string           `json:"contentType,omitempty"`
+	Headers     map[string]any   `json:"headers,omitempty"`
+	Payload     map[string]any   `json:"payload,omitempty"`
+	Examples    []map[string]any `json:"examples,omitempty"`
+}
+
+// FromAnalysis maps the channels of an analysis to an AsyncAPI document titled title. Channels
+// the service publishes to become subscribe operations, channels it consumes publish operations.
+func FromAnalysis(resp *models.AnalysisResponse, title string) *Document {
+	doc := &Document{
+		AsyncAPI: Version,
+		Info: Info{
+			Title:       title,
+			Version:     documentVersion(resp),
+			Description: resp.Summary,
+		},
+		Channels: make(map[string]Channel, len(resp.Channels)),
+	}
+
+	for _, ch := range resp.Channels {
+		item := doc.Channels[ch.Name]
+		item.Description = ch.Description
+		item.Change = ch.Change
+		item.Removed = ch.Change == models.ChannelChangeDeleted
+		if ch.Protocol != "" {
+			item.Bindings = map[string]any{strings.ToLower(ch.Protocol): map[string]any{}}
+		}
+
+		op := &Operation{
+			OperationID: operationID(ch),
+			Summary:     ch.Description,
+			Message:     message(ch.Message),
+		}
+		if ch.Action == models.ChannelActionConsume {
+			item.Publish = op
+		} else {
+			item.Subscribe = op
+		}
+		doc.Channels[ch.Name] = item
+	}
+	return doc
+}
+
+func documentVersion(resp *models.AnalysisResponse) string {
+	if resp.PRNumber > 0 {
+		return fmt.Sprintf("pr-%d", resp.PRNumber)
+	}
+	return "1.0.0"
+}
+
+// operationID derives a stable camelCase id such as sendOrdersCreated from the channel
+func operationID(ch models.Channel) string {
+	verb := "send"
+	if ch.Action == models.ChannelActionConsume {
+		verb = "receive"
+	}
+	var b strings.Builder
+	b.WriteString(verb)
+	for _, part := range strings.FieldsFunc(ch.Name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func message(msg models.ChannelMessage) Message {
+	contentType := msg.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	m := Message{
+		Name:        msg.Name,
+		ContentType: contentType,
+	}
+	if len(msg.Payload) > 0 {
+		m.Payload = schemaOf(msg.Payload)
+		m.Examples = []map[string]any{{"payload": msg.Payload}}
+	}
+	if len(msg.Headers) > 0 {
+		properties := make(map[string]any, len(msg.Headers))
+		for _, header := range msg.Headers {
+			property := map[string]any{"type": "string"}
+			if header.Description != "" {
+				property["description"] = header.Description
+			}
+			if header.Example != nil {
+				property["examples"] = []any{header.Example}
+			}
+			properties[header.Name] = property
+		}
+		m.Headers = map[string]any{"type": "object", "properties": properties}
+	}
+	return m
+}
+
+// schemaOf infers a JSON Schema from an example value
+func schemaOf(value any) map[string]any {
+	switch v := value.(type) {
+	case map[string]any:
+		properties := make(map[string]any, len(v))
+		for key, field := range v {
+			properties[key] = schemaOf(field)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	case []any:
+		schema := map[string]any{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = schemaOf(v[0])
+		}
+		return schema
+	case string:
+		return map[string]any{"type": "string"}
+	case bool:
+		return map[string]any{"type": "boolean"}
+	case float64:
+		if v == float64(int64(v)) {
+			return map[string]any{"type": "integer"}
+		}
+		return map[string]any{"type": "number"}
+	case int, int64:
+		return map[string]any{"type": "integer"}
+	case nil:
+		return map[string]any{"type": "null"}
+	}
+	return map[string]any{}
+}
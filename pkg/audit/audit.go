@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/syslog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+	"github.com/igorsal/pr-documentator/pkg/secrets"
+)
+
+// record is the line written for each event. Seq and PrevHash chain the entries, so removed or
+// edited lines are detectable even though the sink itself is only append-only. The file sink
+// resumes the chain from its last line on startup; syslog starts a new chain with every process.
+type record struct {
+	Seq      uint64 `json:"seq"`
+	PrevHash string `json:"prev_hash"`
+	models.AuditEvent
+}
+
+// Logger writes audit events as JSON lines to a sink kept apart from operational logs
+type Logger struct {
+	mu       sync.Mutex
+	out      io.WriteCloser
+	seq      uint64
+	prevHash string
+	logger   interfaces.Logger
+}
+
+// New opens the configured audit sink. With the "none" sink events are discarded.
+func New(cfg config.AuditConfig, logger interfaces.Logger) (*Logger, error) {
+	l := &Logger{logger: logger}
+
+	switch cfg.Sink {
+	case config.AuditSinkFile:
+		if err := l.resume(cfg.FilePath); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		file, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		l.out = file
+	case config.AuditSinkSyslog:
+		writer, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTH, cfg.SyslogTag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		l.out = writer
+	}
+	return l, nil
+}
+
+// resume continues the chain of the audit log at path from its last record, if any
+func (l *Logger) resume(path string) error {
+	line, err := lastLine(path)
+	if err != nil || line == nil {
+		return err
+	}
+
+	var last record
+	if err := json.Unmarshal(line, &last); err != nil {
+		// Most likely a write cut short by a crash: chain onto it all the same, so the gap shows
+		l.logger.Warn("Audit log ends with an unreadable record", "path", path, "error", err)
+	}
+	l.seq = last.Seq
+	sum := sha256.Sum256(append(line, '\n'))
+	l.prevHash = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// lastLine returns the last non-empty line of the file at path without its newline, nil when
+// the file is missing or empty. The file is read backwards, so its size doesn't matter.
+func lastLine(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var tail []byte
+	for end := info.Size(); end > 0; {
+		start := max(end-chunkSize, 0)
+		chunk := make([]byte, end-start)
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		end = start
+
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if end == 0 && len(trimmed) > 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// Record appends event to the audit log, redacting secrets in its string details
+func (l *Logger) Record(event models.AuditEvent) {
+	if l == nil || l.out == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	for key, value := range event.Details {
+		if text, ok := value.(string); ok {
+			event.Details[key], _ = secrets.Redact(text)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	line, err := json.Marshal(record{Seq: l.seq, PrevHash: l.prevHash, AuditEvent: event})
+	if err != nil {
+		l.logger.Error("Failed to encode audit event", err, "action", event.Action)
+		return
+	}
+	line = append(line, '\n')
+
+	if _, err := l.out.Write(line); err != nil {
+		l.logger.Error("Failed to write audit event", err, "action", event.Action)
+		return
+	}
+	sum := sha256.Sum256(line)
+	l.prevHash = hex.EncodeToString(sum[:])
+}
+
+// Close closes the sink
+func (l *Logger) Close() error {
+	if l == nil || l.out == nil {
+		return nil
+	}
+	return l.out.Close()
+}
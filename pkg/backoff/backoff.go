@@ -0,0 +1,92 @@
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Policy describes an exponential backoff with jitter and a maximum delay
+type Policy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter is the fraction (0-1) of each delay that is randomized
+	Jitter float64
+}
+
+// DefaultPolicy is a sensible default for calls to external services
+var DefaultPolicy = Policy{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Duration returns the delay before the given retry attempt (0-based)
+func (p Policy) Duration(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.Initial) * math.Pow(multiplier, float64(attempt))
+	if p.Max > 0 && delay > float64(p.Max) {
+		delay = float64(p.Max)
+	}
+
+	return Jitter(time.Duration(delay), p.Jitter)
+}
+
+// Jitter randomizes d by up to ±fraction of its value
+func Jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || d <= 0 {
+		return d
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	delta := float64(d) * fraction
+	// #nosec G404 -- jitter does not need a cryptographic source
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter case
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Retry calls fn until it succeeds, retryable returns false, maxAttempts is reached or ctx is done.
+// A nil retryable retries every error. maxAttempts <= 0 retries until ctx is done.
+func Retry(ctx context.Context, p Policy, maxAttempts int, retryable func(error) bool, fn func(attempt int) error) error {
+	var err error
+	for attempt := 0; maxAttempts <= 0 || attempt < maxAttempts; attempt++ {
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+		if retryable != nil && !retryable(err) {
+			return err
+		}
+		if maxAttempts > 0 && attempt == maxAttempts-1 {
+			break
+		}
+		if sleepErr := Sleep(ctx, p.Duration(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+	return err
+}
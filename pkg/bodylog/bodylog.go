@@ -0,0 +1,19 @@
+// Package bodylog prepares HTTP bodies of failed external calls for error logs
+package bodylog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igorsal/pr-documentator/pkg/secrets"
+)
+
+// Format redacts credentials from body and truncates it to maxBytes. Redaction runs first so a
+// secret cut in half by the truncation is never logged.
+func Format(body []byte, maxBytes int) string {
+	text, _ := secrets.Redact(string(body))
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+	return fmt.Sprintf("%s... [truncated %d bytes]", strings.ToValidUTF8(text[:maxBytes], ""), len(text)-maxBytes)
+}
@@ -0,0 +1,93 @@
+package diffparse
+
+import (
+	"path"
+	"strings"
+)
+
+// Kinds of declarative files that can define API routes outside of application code
+const (
+	KindOpenAPI        = "openapi"
+	KindTerraform      = "terraform"
+	KindServerless     = "serverless"
+	KindCloudFormation = "cloudformation"
+)
+
+// DeclarativeFile is a changed file that defines routes through configuration rather than code
+type DeclarativeFile struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"` // one of the Kind constants
+}
+
+// terraformAPIMarkers identify Terraform resources that declare API gateway routes
+var terraformAPIMarkers = []string{
+	"aws_api_gateway_", "aws_apigatewayv2_", "google_api_gateway_", "azurerm_api_management_api",
+}
+
+// DeclarativeFiles detects OpenAPI specs, Terraform, serverless.yml and CloudFormation/SAM templates
+// in a git diff, by file name and by the content of their hunks
+func DeclarativeFiles(diff string) []DeclarativeFile {
+	var files []DeclarativeFile
+	var current string
+	var content strings.Builder
+
+	flush := func() {
+		if current == "" {
+			return
+		}
+		if kind := declarativeKind(current, content.String()); kind != "" {
+			files = append(files, DeclarativeFile{Path: current, Kind: kind})
+		}
+		content.Reset()
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			current = ""
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				current = line[i+3:]
+			}
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			// For deleted files "+++" is /dev/null, so keep the name from the "---" line
+			if name := strings.TrimPrefix(strings.TrimPrefix(line[4:], "b/"), "a/"); name != "/dev/null" && current == "" {
+				current = name
+			}
+		default:
+			content.WriteString(line)
+			content.WriteByte('\n')
+		}
+	}
+	flush()
+
+	return files
+}
+
+func declarativeKind(filePath, content string) string {
+	base := strings.ToLower(path.Base(filePath))
+	ext := path.Ext(base)
+	lower := strings.ToLower(content)
+
+	switch {
+	case strings.HasPrefix(base, "serverless."):
+		return KindServerless
+	case ext == ".tf" || strings.HasSuffix(base, ".tf.json"):
+		for _, marker := range terraformAPIMarkers {
+			if strings.Contains(lower, marker) {
+				return KindTerraform
+			}
+		}
+		return ""
+	case ext != ".yaml" && ext != ".yml" && ext != ".json":
+		return ""
+	case strings.Contains(base, "openapi") || strings.Contains(base, "swagger"),
+		strings.Contains(lower, "openapi:"), strings.Contains(lower, `"openapi":`),
+		strings.Contains(lower, "swagger:"), strings.Contains(lower, `"swagger":`):
+		return KindOpenAPI
+	case strings.Contains(content, "AWS::Serverless::"), strings.Contains(content, "AWS::ApiGateway"):
+		return KindCloudFormation
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package diffparse
+
+import (
+	"strings"
+)
+
+// FileRename describes a file moved or renamed in a git diff
+type FileRename struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Similarity string `json:"similarity,omitempty"` // e.g. "95%", as reported by git
+}
+
+// Renames extracts renamed files from the "rename from" / "rename to" extended headers of a git diff
+func Renames(diff string) []FileRename {
+	var renames []FileRename
+	var current *FileRename
+
+	for _, line := range strings.Split(diff, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = nil
+		case strings.HasPrefix(line, "similarity index "):
+			current = &FileRename{Similarity: strings.TrimPrefix(line, "similarity index ")}
+		case strings.HasPrefix(line, "rename from "):
+			if current == nil {
+				current = &FileRename{}
+			}
+			current.From = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			if current == nil {
+				continue
+			}
+			current.To = strings.TrimPrefix(line, "rename to ")
+			if current.From != "" {
+				renames = append(renames, *current)
+			}
+			current = nil
+		}
+	}
+
+	return renames
+}
@@ -0,0 +1,97 @@
+package doctemplate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// Default renders a description, auth requirements, parameter and header tables and an example curl
+//
+//go:embed default.md.tmpl
+var Default string
+
+// funcs are available to every description template
+var funcs = template.FuncMap{
+	"curl":  Curl,
+	"cell":  cell,
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+}
+
+// Load parses the Markdown description template in file, or Default when file is empty. Templates
+// are executed with a models.APIRoute.
+func Load(file string) (*template.Template, error) {
+	text := Default
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read description template: %w", err)
+		}
+		text = string(data)
+	}
+	return template.New("item_description").Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+// Render executes tmpl for route
+func Render(tmpl *template.Template, route models.APIRoute) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, route); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Curl builds an example curl command for route against the {{baseUrl}} collection variable,
+// using parameter examples where Claude provided them
+func Curl(route models.APIRoute) string {
+	path := route.Path
+	var query []string
+	headers := make([]string, 0, len(route.Headers))
+	for _, header := range route.Headers {
+		headers = append(headers, fmt.Sprintf("%s: %s", header.Name, exampleValue(header.Example, "<"+header.Name+">")))
+	}
+	for _, param := range route.Parameters {
+		value := exampleValue(param.Example, "<"+param.Name+">")
+		switch param.In {
+		case "query":
+			query = append(query, param.Name+"="+value)
+		case "header":
+			headers = append(headers, param.Name+": "+value)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -X %s '{{baseUrl}}%s'", strings.ToUpper(route.Method), path)
+	for _, header := range headers {
+		fmt.Fprintf(&b, " \\\n  -H '%s'", header)
+	}
+	if len(route.RequestBody) > 0 {
+		body, err := json.Marshal(route.RequestBody)
+		if err == nil {
+			fmt.Fprintf(&b, " \\\n  -H 'Content-Type: application/json' \\\n  -d '%s'", body)
+		}
+	}
+	return b.String()
+}
+
+func exampleValue(example any, fallback string) string {
+	if example == nil {
+		return fallback
+	}
+	return fmt.Sprintf("%v", example)
+}
+
+// cell makes text safe to put in a Markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,51 @@
+package errors
+
+import "strings"
+
+// Stable machine-readable error codes surfaced as "code" in error responses.
+// Clients should branch on these rather than on messages, which may change.
+const (
+	// Request errors
+	CodeValidationFailed       = "VALIDATION_FAILED"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodeInvalidRequestBody     = "INVALID_REQUEST_BODY"
+	CodeDiffRequired           = "DIFF_REQUIRED"
+	CodeDiffTooLarge           = "DIFF_TOO_LARGE"
+	CodeInvalidAnalysisTimeout = "INVALID_ANALYSIS_TIMEOUT"
+	CodeTooManyRoutes          = "TOO_MANY_ROUTES"
+	CodeSecretsDetected        = "SECRETS_DETECTED"
+	CodeInvalidCollectionID    = "INVALID_COLLECTION_ID"
+	CodePromptTooLarge         = "PROMPT_TOO_LARGE"
+
+	// Generic errors
+	CodeNotFound     = "NOT_FOUND"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeInternal     = "INTERNAL_ERROR"
+
+	// Upstream service errors
+	CodeClaudeUnauthorized  = "CLAUDE_UNAUTHORIZED"
+	CodeClaudeRateLimited   = "CLAUDE_RATE_LIMITED"
+	CodeClaudeUnavailable   = "CLAUDE_UNAVAILABLE"
+	CodeClaudeCircuitOpen   = "CLAUDE_CIRCUIT_OPEN"
+	CodeClaudeError         = "CLAUDE_ERROR"
+	CodeClaudeNoToolUse     = "CLAUDE_NO_TOOL_USE"
+	CodePostmanUnauthorized = "POSTMAN_UNAUTHORIZED"
+	CodePostmanNotFound     = "POSTMAN_NOT_FOUND"
+	CodePostmanRateLimited  = "POSTMAN_RATE_LIMITED"
+	CodePostmanUnavailable  = "POSTMAN_UNAVAILABLE"
+	CodePostmanError        = "POSTMAN_ERROR"
+	CodePostmanBusy         = "POSTMAN_BUSY" // another write held the collection past POSTMAN_WRITE_LOCK_TIMEOUT
+)
+
+// serviceCode builds the default code for service-scoped errors, e.g. ("claude", "RATE_LIMITED") -> CLAUDE_RATE_LIMITED
+func serviceCode(service, suffix string) string {
+	return strings.ToUpper(service) + "_" + suffix
+}
+
+// CodeOf returns the code of the first AppError in err's chain, or CodeInternal
+func CodeOf(err error) string {
+	if appErr, ok := AsAppError(err); ok && appErr.Code != "" {
+		return appErr.Code
+	}
+	return CodeInternal
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -53,11 +54,18 @@ func (e *AppError) WithCause(cause error) *AppError {
 	return e
 }
 
+// WithCode overrides the default error code with a more specific one
+func (e *AppError) WithCode(code string) *AppError {
+	e.Code = code
+	return e
+}
+
 // Error constructors
 func NewValidationError(message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeValidation,
 		Message:    message,
+		Code:       CodeValidationFailed,
 		StatusCode: http.StatusBadRequest,
 	}
 }
@@ -66,6 +74,7 @@ func NewNotFoundError(message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeNotFound,
 		Message:    message,
+		Code:       CodeNotFound,
 		StatusCode: http.StatusNotFound,
 	}
 }
@@ -74,6 +83,7 @@ func NewUnauthorizedError(message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeUnauthorized,
 		Message:    message,
+		Code:       CodeUnauthorized,
 		StatusCode: http.StatusUnauthorized,
 	}
 }
@@ -82,6 +92,7 @@ func NewExternalError(service, message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeExternal,
 		Message:    fmt.Sprintf("%s service error: %s", service, message),
+		Code:       serviceCode(service, "ERROR"),
 		StatusCode: http.StatusBadGateway,
 		Context:    map[string]any{"service": service},
 	}
@@ -91,6 +102,7 @@ func NewInternalError(message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeInternal,
 		Message:    message,
+		Code:       CodeInternal,
 		StatusCode: http.StatusInternalServerError,
 	}
 }
@@ -99,6 +111,7 @@ func NewRateLimitError(service string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeRateLimit,
 		Message:    fmt.Sprintf("Rate limit exceeded for %s", service),
+		Code:       serviceCode(service, "RATE_LIMITED"),
 		StatusCode: http.StatusTooManyRequests,
 		Context:    map[string]any{"service": service},
 	}
@@ -108,6 +121,7 @@ func NewTimeoutError(service string, timeout string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeTimeout,
 		Message:    fmt.Sprintf("Timeout calling %s after %s", service, timeout),
+		Code:       serviceCode(service, "TIMEOUT"),
 		StatusCode: http.StatusGatewayTimeout,
 		Context:    map[string]any{"service": service, "timeout": timeout},
 	}
@@ -117,20 +131,22 @@ func NewUnavailableError(service string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeUnavailable,
 		Message:    fmt.Sprintf("Service %s is unavailable", service),
+		Code:       serviceCode(service, "UNAVAILABLE"),
 		StatusCode: http.StatusServiceUnavailable,
 		Context:    map[string]any{"service": service},
 	}
 }
 
-// IsAppError checks if an error is an AppError
+// IsAppError checks if an error is, or wraps, an AppError
 func IsAppError(err error) bool {
-	_, ok := err.(*AppError)
+	_, ok := AsAppError(err)
 	return ok
 }
 
-// AsAppError attempts to cast an error to AppError
+// AsAppError returns the first AppError in err's chain
 func AsAppError(err error) (*AppError, bool) {
-	appErr, ok := err.(*AppError)
+	var appErr *AppError
+	ok := errors.As(err, &appErr)
 	return appErr, ok
 }
 
@@ -139,6 +155,7 @@ func WrapError(err error, message string) *AppError {
 	return &AppError{
 		Type:       ErrorTypeInternal,
 		Message:    message,
+		Code:       CodeInternal,
 		StatusCode: http.StatusInternalServerError,
 		Cause:      err,
 	}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// typeStatus maps every ErrorType to the HTTP status returned to clients
+var typeStatus = map[ErrorType]int{
+	ErrorTypeValidation:   http.StatusBadRequest,
+	ErrorTypeNotFound:     http.StatusNotFound,
+	ErrorTypeUnauthorized: http.StatusUnauthorized,
+	ErrorTypeExternal:     http.StatusBadGateway,
+	ErrorTypeInternal:     http.StatusInternalServerError,
+	ErrorTypeRateLimit:    http.StatusTooManyRequests,
+	ErrorTypeTimeout:      http.StatusGatewayTimeout,
+	ErrorTypeUnavailable:  http.StatusServiceUnavailable,
+}
+
+// HTTPStatus returns the HTTP status code for err. AppErrors map by type, an expired context
+// deadline is a gateway timeout and anything else is an internal error.
+func HTTPStatus(err error) int {
+	if appErr, ok := AsAppError(err); ok {
+		if status, ok := typeStatus[appErr.Type]; ok {
+			return status
+		}
+		if appErr.StatusCode != 0 {
+			return appErr.StatusCode
+		}
+		return http.StatusInternalServerError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,72 @@
+// Package events publishes analysis lifecycle events as JSON lines for analytics pipelines
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/igorsal/pr-documentator/internal/config"
+	"github.com/igorsal/pr-documentator/internal/interfaces"
+	"github.com/igorsal/pr-documentator/internal/models"
+)
+
+// Emitter writes events to the configured sink, one JSON object per line
+type Emitter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer // nil for stdout
+	logger interfaces.Logger
+}
+
+// New opens the configured event sink. With the "none" sink events are discarded.
+func New(cfg config.EventsConfig, logger interfaces.Logger) (*Emitter, error) {
+	e := &Emitter{logger: logger}
+
+	switch cfg.Sink {
+	case config.EventsSinkStdout:
+		e.out = os.Stdout
+	case config.EventsSinkFile:
+		file, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event file: %w", err)
+		}
+		e.out = file
+		e.closer = file
+	}
+	return e, nil
+}
+
+// Emit publishes event. Failures are logged, never returned, so analytics can't fail an analysis.
+func (e *Emitter) Emit(event models.Event) {
+	if e == nil || e.out == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		e.logger.Error("Failed to encode analytics event", err, "type", event.Type)
+		return
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.out.Write(line); err != nil {
+		e.logger.Error("Failed to write analytics event", err, "type", event.Type)
+	}
+}
+
+// Close closes the sink
+func (e *Emitter) Close() error {
+	if e == nil || e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
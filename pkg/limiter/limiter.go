@@ -0,0 +1,113 @@
+// Package limiter bounds concurrent calls to a rate-limited service
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Adaptive bounds how many callers hold a slot at once, and shrinks below its configured size
+// while the service reports that its request quota is running out
+type Adaptive struct {
+	mu        sync.Mutex
+	max       int
+	inUse     int
+	throttled int       // concurrency allowed until until
+	until     time.Time // zero when not throttled
+	changed   chan struct{}
+	report    func(limit, inUse int)
+}
+
+// New creates a limiter allowing max concurrent slots (at least 1). report, if not nil, is called
+// with the current limit and slots in use whenever either changes.
+func New(max int, report func(limit, inUse int)) *Adaptive {
+	if max <= 0 {
+		max = 1
+	}
+	if report == nil {
+		report = func(int, int) {}
+	}
+	l := &Adaptive{max: max, changed: make(chan struct{}), report: report}
+	l.mu.Lock()
+	l.reportLocked(l.limitLocked(time.Now()))
+	l.mu.Unlock()
+	return l
+}
+
+// Acquire waits for a free slot or until ctx is done
+func (l *Adaptive) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		limit := l.limitLocked(time.Now())
+		if l.inUse < limit {
+			l.inUse++
+			l.reportLocked(limit)
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		wait := time.Until(l.until)
+		l.mu.Unlock()
+
+		// Re-check when a slot frees up, the quota changes or the throttle expires
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *Adaptive) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inUse--
+	l.reportLocked(l.limitLocked(time.Now()))
+	l.signalLocked()
+}
+
+// Observe adapts the limit to the request quota the service reports: never more calls in flight
+// than requests left before reset
+func (l *Adaptive) Observe(remaining int, reset time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if remaining >= l.max {
+		l.until = time.Time{}
+	} else {
+		l.throttled = max(remaining, 0)
+		l.until = reset
+	}
+	l.reportLocked(l.limitLocked(time.Now()))
+	l.signalLocked()
+}
+
+func (l *Adaptive) limitLocked(now time.Time) int {
+	if !l.until.IsZero() && now.Before(l.until) {
+		return l.throttled
+	}
+	return l.max
+}
+
+func (l *Adaptive) signalLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func (l *Adaptive) reportLocked(limit int) {
+	l.report(limit, l.inUse)
+}
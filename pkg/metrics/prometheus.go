@@ -12,15 +12,21 @@ type PrometheusCollector struct {
 	counters   map[string]*prometheus.CounterVec
 	histograms map[string]*prometheus.HistogramVec
 	gauges     map[string]*prometheus.GaugeVec
+	// constLabels are attached to every metric, e.g. the deployment environment
+	constLabels prometheus.Labels
 }
 
-// NewPrometheusCollector creates a new Prometheus metrics collector
-func NewPrometheusCollector() interfaces.MetricsCollector {
+// NewPrometheusCollector creates a new Prometheus metrics collector.
+// A non-empty environment is added as a constant "environment" label to all metrics.
+func NewPrometheusCollector(environment string) interfaces.MetricsCollector {
 	collector := &PrometheusCollector{
 		counters:   make(map[string]*prometheus.CounterVec),
 		histograms: make(map[string]*prometheus.HistogramVec),
 		gauges:     make(map[string]*prometheus.GaugeVec),
 	}
+	if environment != "" {
+		collector.constLabels = prometheus.Labels{"environment": environment}
+	}
 
 	// Initialize common metrics
 	collector.initializeMetrics()
@@ -32,96 +38,298 @@ func (p *PrometheusCollector) initializeMetrics() {
 	// HTTP request metrics
 	p.counters["http_requests_total"] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_http_requests_total",
-			Help: "Total number of HTTP requests",
+			Name:        "pr_documentator_http_requests_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of HTTP requests",
 		},
 		[]string{"method", "endpoint", "status_code"},
 	)
 
 	p.histograms["http_request_duration_seconds"] = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pr_documentator_http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
-			Buckets: prometheus.DefBuckets,
+			Name:        "pr_documentator_http_request_duration_seconds",
+			ConstLabels: p.constLabels,
+			Help:        "HTTP request duration in seconds",
+			Buckets:     prometheus.DefBuckets,
 		},
 		[]string{"method", "endpoint", "status_code"},
 	)
 
+	p.counters["idempotency_requests_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_idempotency_requests_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of idempotency cache lookups",
+		},
+		[]string{"result"}, // result: replay, miss, forced
+	)
+
+	p.counters["analysis_cache_requests_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_analysis_cache_requests_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of analysis cache lookups by entry point",
+		},
+		[]string{"entry_point", "result"}, // entry_point: webhook, manual, queue; result: hit, miss
+	)
+
 	// Claude API metrics
 	p.counters["claude_requests_total"] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_claude_requests_total",
-			Help: "Total number of Claude API requests",
+			Name:        "pr_documentator_claude_requests_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of Claude API requests",
 		},
 		[]string{"service", "operation", "status", "repository"},
 	)
 
 	p.histograms["claude_request_duration_seconds"] = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pr_documentator_claude_request_duration_seconds",
-			Help:    "Claude API request duration in seconds",
-			Buckets: []float64{0.1, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0},
+			Name:        "pr_documentator_claude_request_duration_seconds",
+			ConstLabels: p.constLabels,
+			Help:        "Claude API request duration in seconds",
+			Buckets:     []float64{0.1, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0},
 		},
 		[]string{"service", "operation", "repository"},
 	)
 
+	p.counters["claude_input_tokens_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_claude_input_tokens_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of Claude input tokens consumed",
+		},
+		[]string{"repository", "model"},
+	)
+
+	p.counters["claude_output_tokens_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_claude_output_tokens_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of Claude output tokens generated",
+		},
+		[]string{"repository", "model"},
+	)
+
+	p.gauges["claude_estimated_cost_usd"] = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "pr_documentator_claude_estimated_cost_usd",
+			ConstLabels: p.constLabels,
+			Help:        "Estimated cumulative Claude API cost in USD since process start",
+		},
+		[]string{"repository", "model"},
+	)
+
+	p.gauges["batch_concurrency"] = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "pr_documentator_batch_concurrency",
+			ConstLabels: p.constLabels,
+			Help:        "Concurrency of batch analyses: current limit and slots in use",
+		},
+		[]string{"state"}, // state: limit, in_use
+	)
+
+	p.gauges["claude_chunk_concurrency"] = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "pr_documentator_claude_chunk_concurrency",
+			ConstLabels: p.constLabels,
+			Help:        "Concurrency of diff chunk summarization: current limit and slots in use",
+		},
+		[]string{"state"}, // state: limit, in_use
+	)
+
+	p.counters["claude_chunks_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_claude_chunks_total",
+			ConstLabels: p.constLabels,
+			Help:        "Diff chunks summarized before analysis, by outcome",
+		},
+		[]string{"outcome"}, // summarized, retried, excluded
+	)
+
 	// Postman API metrics
 	p.counters["postman_requests_total"] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_postman_requests_total",
-			Help: "Total number of Postman API requests",
+			Name:        "pr_documentator_postman_requests_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of Postman API requests",
 		},
 		[]string{"service", "operation", "status"},
 	)
 
 	p.histograms["postman_request_duration_seconds"] = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pr_documentator_postman_request_duration_seconds",
-			Help:    "Postman API request duration in seconds",
-			Buckets: []float64{0.1, 0.5, 1.0, 2.5, 5.0, 10.0},
+			Name:        "pr_documentator_postman_request_duration_seconds",
+			ConstLabels: p.constLabels,
+			Help:        "Postman API request duration in seconds",
+			Buckets:     []float64{0.1, 0.5, 1.0, 2.5, 5.0, 10.0},
 		},
 		[]string{"service", "operation"},
 	)
 
+	p.counters["postman_rate_limited_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_postman_rate_limited_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of Postman API responses rejected with HTTP 429",
+		},
+		[]string{"operation"},
+	)
+
+	p.histograms["postman_write_batch_size"] = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "pr_documentator_postman_write_batch_size",
+			ConstLabels: p.constLabels,
+			Help:        "Number of analyses saved to a Postman collection with a single PUT",
+			Buckets:     []float64{1, 2, 3, 5, 10, 20},
+		},
+		[]string{},
+	)
+
+	p.counters["postman_write_lock_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_postman_write_lock_total",
+			ConstLabels: p.constLabels,
+			Help:        "Acquisitions of a Postman collection write lock, by whether another write held it",
+		},
+		[]string{"outcome"}, // free, contended, timeout
+	)
+
+	p.histograms["postman_write_lock_wait_seconds"] = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "pr_documentator_postman_write_lock_wait_seconds",
+			ConstLabels: p.constLabels,
+			Help:        "Time spent waiting for a Postman collection write lock held by another write",
+			Buckets:     prometheus.DefBuckets,
+		},
+		[]string{},
+	)
+
+	p.counters["postman_write_conflicts_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_postman_write_conflicts_total",
+			ConstLabels: p.constLabels,
+			Help:        "Postman collection writes rejected because the collection changed since it was read",
+		},
+		[]string{"outcome"},
+	)
+
+	p.counters["postman_jobs_pruned_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_postman_jobs_pruned_total",
+			ConstLabels: p.constLabels,
+			Help:        "Finished background Postman update records pruned, by reason (age or count)",
+		},
+		[]string{"reason"},
+	)
+
+	p.counters["secrets_refresh_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_secrets_refresh_total",
+			Help:        "Total number of secret manager refreshes",
+			ConstLabels: p.constLabels,
+		},
+		[]string{"outcome"}, // unchanged, rotated, error
+	)
+
+	p.counters["postman_webhook_events_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_postman_webhook_events_total",
+			Help:        "Total number of Postman collection-change webhook events received",
+			ConstLabels: p.constLabels,
+		},
+		[]string{"outcome"}, // invalidated, logged, unauthorized, invalid
+	)
+
 	// Business metrics
 	p.counters["pr_analysis_total"] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_pr_analysis_total",
-			Help: "Total number of PR analyses performed",
+			Name:        "pr_documentator_pr_analysis_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of PR analyses performed",
 		},
 		[]string{"repository", "action", "status"},
 	)
 
 	p.histograms["pr_analysis_duration_seconds"] = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pr_documentator_pr_analysis_duration_seconds",
-			Help:    "PR analysis duration in seconds",
-			Buckets: []float64{1.0, 5.0, 10.0, 30.0, 60.0, 120.0},
+			Name:        "pr_documentator_pr_analysis_duration_seconds",
+			ConstLabels: p.constLabels,
+			Help:        "PR analysis duration in seconds",
+			Buckets:     []float64{1.0, 5.0, 10.0, 30.0, 60.0, 120.0},
 		},
 		[]string{"repository", "action"},
 	)
 
 	p.gauges["api_routes_discovered"] = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "pr_documentator_api_routes_discovered",
-			Help: "Number of API routes discovered in PR analysis",
+			Name:        "pr_documentator_api_routes_discovered",
+			ConstLabels: p.constLabels,
+			Help:        "Number of API routes discovered in PR analysis",
 		},
 		[]string{"repository", "type"}, // type: new, modified, deleted
 	)
 
+	p.counters["diff_secrets_detected_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_diff_secrets_detected_total",
+			Help:        "Total number of secrets detected in PR diffs",
+			ConstLabels: p.constLabels,
+		},
+		[]string{"kind"},
+	)
+
+	p.counters["queue_messages_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_queue_messages_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of queued PR events processed",
+		},
+		[]string{"status"}, // status: success, partial, error, invalid
+	)
+
+	p.counters["output_webhook_deliveries_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_output_webhook_deliveries_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of output webhook deliveries",
+		},
+		[]string{"status"},
+	)
+
+	p.counters["github_check_runs_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_github_check_runs_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of GitHub Check Runs created for analyses",
+		},
+		[]string{"status"},
+	)
+
 	// Circuit breaker metrics
+	p.counters["breaker_fallback_total"] = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "pr_documentator_breaker_fallback_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total number of analyses handled by the fallback while the Claude circuit breaker was open",
+		},
+		[]string{"mode"}, // mode: queue, cache
+	)
+
 	p.gauges["circuit_breaker_state"] = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "pr_documentator_circuit_breaker_state",
-			Help: "Circuit breaker state (0=closed, 1=open, 2=half-open)",
+			Name:        "pr_documentator_circuit_breaker_state",
+			ConstLabels: p.constLabels,
+			Help:        "Circuit breaker state (0=closed, 1=open, 2=half-open)",
 		},
 		[]string{"service", "name"},
 	)
 
 	p.counters["circuit_breaker_events_total"] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_circuit_breaker_events_total",
-			Help: "Total circuit breaker events",
+			Name:        "pr_documentator_circuit_breaker_events_total",
+			ConstLabels: p.constLabels,
+			Help:        "Total circuit breaker events",
 		},
 		[]string{"service", "name", "event"}, // event: success, failure, timeout, rejection
 	)
@@ -137,6 +345,16 @@ func (p *PrometheusCollector) IncrementCounter(name string, labels map[string]st
 	counter.With(labels).Inc()
 }
 
+// AddCounter adds an arbitrary non-negative value to a counter metric
+func (p *PrometheusCollector) AddCounter(name string, value float64, labels map[string]string) {
+	counter, exists := p.counters[name]
+	if !exists {
+		return
+	}
+
+	counter.With(labels).Add(value)
+}
+
 // RecordDuration records a duration in a histogram
 func (p *PrometheusCollector) RecordDuration(name string, duration float64, labels map[string]string) {
 	histogram, exists := p.histograms[name]
@@ -165,8 +383,9 @@ func (p *PrometheusCollector) RegisterCustomCounter(name, help string, labels []
 
 	p.counters[name] = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "pr_documentator_" + name,
-			Help: help,
+			Name:        "pr_documentator_" + name,
+			ConstLabels: p.constLabels,
+			Help:        help,
 		},
 		labels,
 	)
@@ -184,9 +403,10 @@ func (p *PrometheusCollector) RegisterCustomHistogram(name, help string, labels
 
 	p.histograms[name] = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "pr_documentator_" + name,
-			Help:    help,
-			Buckets: buckets,
+			Name:        "pr_documentator_" + name,
+			ConstLabels: p.constLabels,
+			Help:        help,
+			Buckets:     buckets,
 		},
 		labels,
 	)
@@ -200,8 +420,9 @@ func (p *PrometheusCollector) RegisterCustomGauge(name, help string, labels []st
 
 	p.gauges[name] = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "pr_documentator_" + name,
-			Help: help,
+			Name:        "pr_documentator_" + name,
+			ConstLabels: p.constLabels,
+			Help:        help,
 		},
 		labels,
 	)
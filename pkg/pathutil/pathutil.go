@@ -0,0 +1,72 @@
+package pathutil
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BaseURLVariable is the Postman variable prefixed to documented request URLs
+const BaseURLVariable = "{{baseUrl}}"
+
+// Normalize canonicalizes an API path for comparison: it drops the {{baseUrl}} prefix and
+// query string, lowercases the scheme and host of absolute URLs, collapses duplicate slashes,
+// ensures a leading slash and strips trailing slashes. Path casing is preserved.
+func Normalize(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, BaseURLVariable)
+
+	prefix := ""
+	if u, err := url.Parse(path); err == nil && u.Scheme != "" && u.Host != "" {
+		prefix = strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+		path = u.Path
+	} else if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+
+	return prefix + path
+}
+
+// Equal reports whether two paths refer to the same endpoint after normalization
+func Equal(a, b string, caseSensitive bool) bool {
+	a, b = Normalize(a), Normalize(b)
+	if caseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+var versionSegment = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+
+// Version returns the first version segment of a path (e.g. "v2" for /api/v2/users), or "" if none
+func Version(path string) string {
+	for _, segment := range strings.Split(Normalize(path), "/") {
+		if versionSegment.MatchString(strings.ToLower(segment)) {
+			return strings.ToLower(segment)
+		}
+	}
+	return ""
+}
+
+// StripVersion replaces the version segment with a placeholder so versions of the same route compare equal
+func StripVersion(path string) string {
+	segments := strings.Split(Normalize(path), "/")
+	for i, segment := range segments {
+		if versionSegment.MatchString(strings.ToLower(segment)) {
+			segments[i] = "{version}"
+			break
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,63 @@
+package pathutil
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "root", path: "/", want: "/"},
+		{name: "empty", path: "", want: "/"},
+		{name: "trailing slash", path: "/api/users/", want: "/api/users"},
+		{name: "multiple trailing slashes", path: "/api/users///", want: "/api/users"},
+		{name: "duplicate slashes", path: "/api//v1///users", want: "/api/v1/users"},
+		{name: "missing leading slash", path: "api/users", want: "/api/users"},
+		{name: "baseUrl prefix", path: "{{baseUrl}}/api/users", want: "/api/users"},
+		{name: "baseUrl prefix without slash", path: "{{baseUrl}}api/users/", want: "/api/users"},
+		{name: "query string", path: "/api/users?page=2&limit=10", want: "/api/users"},
+		{name: "fragment", path: "/api/users#section", want: "/api/users"},
+		{name: "surrounding whitespace", path: "  /api/users  ", want: "/api/users"},
+		{name: "absolute URL with uppercase host", path: "HTTPS://API.Example.COM/api/users/?q=1", want: "https://api.example.com/api/users"},
+		{name: "absolute URL keeps path casing", path: "https://Example.com/API/Users", want: "https://example.com/API/Users"},
+		{name: "path casing preserved", path: "/API/Users", want: "/API/Users"},
+		{name: "path parameters", path: "/users/{id}/", want: "/users/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.path); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name          string
+		a, b          string
+		caseSensitive bool
+		want          bool
+	}{
+		{name: "identical", a: "/api/users", b: "/api/users", caseSensitive: true, want: true},
+		{name: "trailing slash", a: "/api/users/", b: "/api/users", caseSensitive: true, want: true},
+		{name: "duplicate slashes", a: "/api//users", b: "/api/users", caseSensitive: true, want: true},
+		{name: "baseUrl prefix", a: "{{baseUrl}}/api/users", b: "/api/users", caseSensitive: true, want: true},
+		{name: "query string", a: "/api/users?page=1", b: "/api/users", caseSensitive: true, want: true},
+		{name: "absolute URL host casing", a: "https://API.example.com/users", b: "https://api.example.com/users", caseSensitive: true, want: true},
+		{name: "different paths", a: "/api/users", b: "/api/orders", caseSensitive: false, want: false},
+		{name: "case differs, case-sensitive", a: "/API/Users", b: "/api/users", caseSensitive: true, want: false},
+		{name: "case differs, case-insensitive", a: "/API/Users", b: "/api/users", caseSensitive: false, want: true},
+		{name: "case and slashes differ, case-insensitive", a: "{{baseUrl}}//API/Users/", b: "/api/users?x=1", caseSensitive: false, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b, tt.caseSensitive); got != tt.want {
+				t.Errorf("Equal(%q, %q, %v) = %v, want %v", tt.a, tt.b, tt.caseSensitive, got, tt.want)
+			}
+		})
+	}
+}
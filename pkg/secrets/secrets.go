@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"regexp"
+)
+
+// Placeholder replaces every detected secret
+const Placeholder = "[REDACTED_SECRET]"
+
+// pattern is a named secret detector
+type pattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// patterns covers common credential formats. They favour precision over recall so that
+// ordinary code is not mangled before analysis.
+var patterns = []pattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"aws_access_key_id", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_access_key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"anthropic_api_key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{"postman_api_key", regexp.MustCompile(`\bPMAK-[A-Za-z0-9-]{20,}`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"stripe_secret_key", regexp.MustCompile(`\b[sr]k_live_[0-9a-zA-Z]{24,}`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+// Redact replaces detected secrets in text with Placeholder and returns the number found per kind
+func Redact(text string) (string, map[string]int) {
+	found := make(map[string]int)
+	for _, p := range patterns {
+		text = p.re.ReplaceAllStringFunc(text, func(string) string {
+			found[p.kind]++
+			return Placeholder
+		})
+	}
+	return text, found
+}
+
+// Total sums the per-kind counts returned by Redact
+func Total(found map[string]int) int {
+	total := 0
+	for _, n := range found {
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,81 @@
+package secretstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Provider reads every secret of the service from an external secret manager, keyed by the
+// environment variable the secret replaces (CLAUDE_API_KEY, POSTMAN_API_KEY, ...)
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// Store caches the secrets last fetched from a provider. Refresh re-reads them and reports
+// which ones changed, so rotated credentials can be applied without a restart.
+type Store struct {
+	provider Provider
+
+	mu        sync.RWMutex
+	values    map[string]string
+	fetchedAt time.Time
+}
+
+// New creates a store backed by provider; call Refresh to load it
+func New(provider Provider) *Store {
+	return &Store{
+		provider: provider,
+		values:   make(map[string]string),
+	}
+}
+
+// Provider returns the name of the backing provider
+func (s *Store) Provider() string {
+	return s.provider.Name()
+}
+
+// Refresh fetches the secrets and returns the sorted names of those that were added, changed
+// or removed since the previous fetch. The cache is kept as is when the fetch fails.
+func (s *Store) Refresh(ctx context.Context) ([]string, error) {
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed []string
+	for name, value := range values {
+		if previous, ok := s.values[name]; !ok || previous != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range s.values {
+		if _, ok := values[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+
+	s.values = values
+	s.fetchedAt = time.Now()
+	return changed, nil
+}
+
+// Get returns the cached secret name, "" when the provider doesn't hold it
+func (s *Store) Get(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[name]
+}
+
+// FetchedAt returns when the secrets were last fetched successfully
+func (s *Store) FetchedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fetchedAt
+}
@@ -0,0 +1,86 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider reads secrets from one HashiCorp Vault KV secret, version 1 or 2 engine.
+// For KV v2 the path includes the data segment, e.g. secret/data/pr-documentator.
+type VaultProvider struct {
+	addr       string
+	token      string
+	namespace  string
+	path       string
+	httpClient *http.Client
+}
+
+// NewVaultProvider creates a Vault provider authenticated with token
+func NewVaultProvider(addr, token, namespace, path string, httpClient *http.Client) *VaultProvider {
+	return &VaultProvider{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		namespace:  namespace,
+		path:       strings.Trim(path, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// vaultResponse is the body of a Vault secret read. KV v2 nests the secret under data.data.
+type vaultResponse struct {
+	Data map[string]any `json:"data"`
+}
+
+// Name identifies the provider in logs
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch reads the secret; non-string values are formatted as text
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d for %s", resp.StatusCode, p.path)
+	}
+
+	var body vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, v2 := data["metadata"]; v2 {
+			data = nested
+		}
+	}
+
+	values := make(map[string]string, len(data))
+	for name, value := range data {
+		switch v := value.(type) {
+		case string:
+			values[name] = v
+		case nil:
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	pkgerrors "github.com/igorsal/pr-documentator/pkg/errors"
+)
+
+// FieldError describes a single failed validation rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned when one or more fields fail validation
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fe := range v {
+		messages[i] = fe.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Fields returns the names of the invalid fields
+func (v ValidationErrors) Fields() []string {
+	fields := make([]string, len(v))
+	for i, fe := range v {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+// Struct validates a struct using `validate` tags. Supported rules: required, url.
+// Tagged nested structs are validated recursively and fields are reported by their JSON path.
+func Struct(s any) error {
+	val := reflect.ValueOf(s)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return ValidationErrors{{Field: "", Rule: "required", Message: "value is required"}}
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("validator: expected struct, got %s", val.Kind())
+	}
+
+	var errs ValidationErrors
+	validateStruct(val, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(val reflect.Value, prefix string, errs *ValidationErrors) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fv := val.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		failed := false
+		for _, rule := range strings.Split(tag, ",") {
+			if rule == "" {
+				continue
+			}
+			if fe := checkRule(name, rule, fv); fe != nil {
+				*errs = append(*errs, *fe)
+				failed = true
+			}
+		}
+
+		// Only recurse into tagged nested structs that passed their own rules
+		if fv.Kind() == reflect.Struct && !failed {
+			validateStruct(fv, name, errs)
+		}
+	}
+}
+
+func checkRule(name, rule string, fv reflect.Value) *FieldError {
+	switch rule {
+	case "required":
+		if fv.IsZero() {
+			return &FieldError{Field: name, Rule: rule, Message: fmt.Sprintf("%s is required", name)}
+		}
+	case "url":
+		if fv.Kind() != reflect.String || fv.String() == "" {
+			return nil
+		}
+		if u, err := url.Parse(fv.String()); err != nil || u.Scheme == "" || u.Host == "" {
+			return &FieldError{Field: name, Rule: rule, Message: fmt.Sprintf("%s must be a valid URL", name)}
+		}
+	}
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FieldReason is the client-facing form of a FieldError
+type FieldReason struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Reasons returns one FieldReason per failed rule
+func (v ValidationErrors) Reasons() []FieldReason {
+	reasons := make([]FieldReason, len(v))
+	for i, fe := range v {
+		reasons[i] = FieldReason{Field: fe.Field, Reason: fe.Rule}
+	}
+	return reasons
+}
+
+// ToAppError converts a validation failure into a validation AppError listing each invalid
+// field under the "fields" context key. Other errors become a plain validation error.
+func ToAppError(err error, message string) *pkgerrors.AppError {
+	appErr := pkgerrors.NewValidationError(message)
+	if validationErrs, ok := err.(ValidationErrors); ok {
+		return appErr.WithContext("fields", validationErrs.Reasons())
+	}
+	return appErr.WithCause(err)
+}